@@ -8,6 +8,6 @@ import (
 func main() {
 	application := app.NewApp()
 	if err := application.StartHTTPServer(); err != nil {
-		application.Logger.Fatal("error func main, method StartHTTPServer by path cmd/main.go", zap.Error(err))
+		application.Logger.Fatal("error func main, method StartHTTPServer", zap.Error(err))
 	}
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"log"
+	"math"
+	"os"
+)
+
+// session is a feed shown to the viewer in the past with the candidates the viewer liked
+type session struct {
+	Viewer     *profile.RankViewer         `json:"viewer"`
+	Candidates []*profile.CandidateProfile `json:"candidates"`
+	LikedIDs   []uint64                    `json:"likedIds"`
+}
+
+type dataset struct {
+	Sessions []*session `json:"sessions"`
+}
+
+type evaluation struct {
+	Precision float64
+	NDCG      float64
+	MRR       float64
+}
+
+// The harness replays the recorded feeds through every ranker and reports how high the liked candidates are ranked
+func main() {
+	path := flag.String("dataset", "cmd/rankerEval/fixtures/dataset.json", "path to the fixture dataset")
+	k := flag.Int("k", 3, "number of top candidates to evaluate")
+	seed := flag.Int64("seed", 1, "seed of the shuffle ranker")
+	flag.Parse()
+	ds, err := loadDataset(*path)
+	if err != nil {
+		log.Fatalf("error func main, method loadDataset: %v", err)
+	}
+	rankers := []struct {
+		name   string
+		ranker profileUseCase.Ranker
+	}{
+		{name: profileUseCase.RankerWeighted, ranker: profileUseCase.NewWeightedRanker(
+			profileUseCase.DefaultRankWeights())},
+		{name: profileUseCase.RankerShuffle, ranker: profileUseCase.NewShuffleRanker(*seed)},
+	}
+	fmt.Printf("sessions: %d, k: %d\n", len(ds.Sessions), *k)
+	fmt.Printf("%-10s %12s %8s %8s\n", "ranker", "precision@k", "ndcg@k", "mrr")
+	for _, rk := range rankers {
+		e := evaluate(rk.ranker, ds, *k)
+		fmt.Printf("%-10s %12.3f %8.3f %8.3f\n", rk.name, e.Precision, e.NDCG, e.MRR)
+	}
+}
+
+func loadDataset(path string) (*dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ds := dataset{}
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// evaluate returns the metrics averaged over the sessions. Every session is copied,
+// because rankers reorder candidates in place.
+func evaluate(rk profileUseCase.Ranker, ds *dataset, k int) *evaluation {
+	e := evaluation{}
+	if len(ds.Sessions) == 0 {
+		return &e
+	}
+	for _, s := range ds.Sessions {
+		liked := make(map[uint64]bool, len(s.LikedIDs))
+		for _, id := range s.LikedIDs {
+			liked[id] = true
+		}
+		candidates := make([]*profile.CandidateProfile, 0, len(s.Candidates))
+		for _, c := range s.Candidates {
+			cc := *c
+			candidates = append(candidates, &cc)
+		}
+		ranked := rk.Rank(s.Viewer, candidates)
+		hits := 0
+		dcg := 0.0
+		rr := 0.0
+		for i, c := range ranked {
+			if !liked[c.Profile.ID] {
+				continue
+			}
+			if rr == 0 {
+				rr = 1 / float64(i+1)
+			}
+			if i < k {
+				hits++
+				dcg += 1 / math.Log2(float64(i+2))
+			}
+		}
+		idcg := 0.0
+		for i := 0; i < k && i < len(liked); i++ {
+			idcg += 1 / math.Log2(float64(i+2))
+		}
+		e.Precision += float64(hits) / float64(k)
+		if idcg > 0 {
+			e.NDCG += dcg / idcg
+		}
+		e.MRR += rr
+	}
+	n := float64(len(ds.Sessions))
+	e.Precision /= n
+	e.NDCG /= n
+	e.MRR /= n
+	return &e
+}
@@ -0,0 +1,56 @@
+package psqlRepo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+// DB starts a client span with the SQL statement for every query which is run through it. The statements of
+// the transactions are not traced separately, they belong to the span of the repository method.
+type DB struct {
+	*sql.DB
+}
+
+func NewDB(db *sql.DB) *DB {
+	return &DB{DB: db}
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	tracing.RecordError(span, err)
+	return rows, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != sql.ErrNoRows {
+		tracing.RecordError(span, err)
+	}
+	return row
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	tracing.RecordError(span, err)
+	return result, err
+}
+
+// startQuerySpan names the span by the SQL operation, e.g. SELECT
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		name = strings.ToUpper(fields[0])
+	}
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(query)))
+}
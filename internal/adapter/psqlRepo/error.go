@@ -0,0 +1,48 @@
+package psqlRepo
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/lib/pq"
+)
+
+// The postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	codeUniqueViolation       = "23505"
+	codeForeignKeyViolation   = "23503"
+	codeNotNullViolation      = "23502"
+	codeCheckViolation        = "23514"
+	codeStringDataTruncation  = "22001"
+	codeInvalidTextExpression = "22P02"
+)
+
+// MapError maps the error of the database to the domain error: no rows to not found, the constraint violations
+// to conflict, not found and validation errors and the rest to the internal error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := appError.As(err); ok {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return appError.Wrap(err, appError.KindNotFound, "not found")
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case codeUniqueViolation:
+			return appError.Wrap(err, appError.KindConflict, "already exists")
+		case codeForeignKeyViolation:
+			return appError.Wrap(err, appError.KindNotFound, "referenced record not found")
+		case codeNotNullViolation, codeCheckViolation, codeStringDataTruncation, codeInvalidTextExpression:
+			e := &appError.Error{Kind: appError.KindValidation, Message: "invalid value", Err: err}
+			if pqErr.Column != "" {
+				e.Fields = []*appError.FieldError{{Field: pqErr.Column, Message: pqErr.Message}}
+			}
+			return e
+		}
+	}
+	return appError.NewInternal(err)
+}
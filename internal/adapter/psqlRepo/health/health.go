@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/health"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"go.uber.org/zap"
+)
+
+type RepositoryHealth struct {
+	logger logger.Logger
+	db     *sql.DB
+}
+
+func NewRepositoryHealth(l logger.Logger, db *sql.DB) *RepositoryHealth {
+	return &RepositoryHealth{logger: l, db: db}
+}
+
+func (r *RepositoryHealth) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		r.logger.With(ctx).Error("error func Ping, method PingContext", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindMigrationVersion returns the version applied by migrate, false is returned when no migration has been applied
+func (r *RepositoryHealth) FindMigrationVersion(ctx context.Context) (*health.MigrationVersion, bool, error) {
+	v := health.MigrationVersion{}
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query).Scan(&v.Version, &v.Dirty)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindMigrationVersion, method Scan", zap.Error(err))
+		return nil, false, err
+	}
+	return &v, true, nil
+}
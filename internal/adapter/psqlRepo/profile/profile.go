@@ -3,30 +3,43 @@ package profile
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/adapter/psqlRepo"
 	"github.com/EvgeniyBudaev/love-server/internal/entity/pagination"
 	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
 	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
 	useCaseProfile "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"math"
 	"strconv"
 	"time"
 )
 
+// repositoryName labels the query durations of the repository
+const repositoryName = "profile"
+
 type RepositoryProfile struct {
-	logger logger.Logger
-	db     *sql.DB
+	logger  logger.Logger
+	db      *psqlRepo.DB
+	metrics *metrics.Metrics
 }
 
-func NewRepositoryProfile(logger logger.Logger, db *sql.DB) useCaseProfile.Store {
+func NewRepositoryProfile(logger logger.Logger, db *sql.DB, m *metrics.Metrics) useCaseProfile.Store {
 	return &RepositoryProfile{
-		logger: logger,
-		db:     db,
+		logger:  logger,
+		db:      psqlRepo.NewDB(db),
+		metrics: m,
 	}
 }
 
 func (r *RepositoryProfile) Add(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "Add", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.Add")
+	defer span.End()
 	birthday := p.Birthday.Format("2006-01-02")
 	query := "INSERT INTO profiles (session_id, display_name, birthday, gender, location, description," +
 		" height, weight, is_deleted, is_blocked, is_premium, is_show_distance, is_invisible," +
@@ -36,19 +49,21 @@ func (r *RepositoryProfile) Add(ctx context.Context, p *profile.Profile) (*profi
 		&p.Description, &p.Height, &p.Weight, p.IsDeleted, &p.IsBlocked, &p.IsPremium, &p.IsShowDistance,
 		&p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func Add, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func Add, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) Update(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "Update", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.Update")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug(
-			"error func Update, method Begin by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error(
+			"error func Update, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profiles SET display_name=$1, birthday=$2, gender=$3, location=$4," +
@@ -58,264 +73,436 @@ func (r *RepositoryProfile) Update(ctx context.Context, p *profile.Profile) (*pr
 		&p.Description, &p.Height, &p.Weight, &p.IsBlocked, &p.IsPremium, &p.IsShowDistance,
 		&p.IsInvisible, &p.UpdatedAt, &p.LastOnline, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func Update, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func Update, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateLastOnline(ctx context.Context, profileID uint64) error {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateLastOnline", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateLastOnline")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateLastOnline, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return err
+		r.logger.With(ctx).Error("error func UpdateLastOnline, method Begin", zap.Error(err))
+		return psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profiles SET last_online=$1 WHERE id=$2"
 	_, err = r.db.ExecContext(ctx, query, time.Now().UTC(), profileID)
 	if err != nil {
-		r.logger.Debug("error func UpdateLastOnline, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return err
+		r.logger.With(ctx).Error("error func UpdateLastOnline, method ExecContext", zap.Error(err))
+		return psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return nil
 }
 
 func (r *RepositoryProfile) Delete(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "Delete", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.Delete")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug(
-			"error func Delete, method Begin by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error(
+			"error func Delete, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profiles SET session_id=$1, display_name=$2, birthday=$3, gender=$4, location=$5," +
 		" description=$6, height=$7, weight=$8, is_deleted=$9, is_blocked=$10, is_premium=$11," +
-		" is_show_distance=$12, is_invisible=$13, updated_at=$14, last_online=$15 WHERE id=$16"
+		" is_show_distance=$12, is_invisible=$13, updated_at=$14, last_online=$15, deleted_at=$14 WHERE id=$16"
 	_, err = r.db.ExecContext(ctx, query, &p.SessionID, &p.DisplayName, &p.Birthday, &p.Gender, &p.Location,
 		&p.Description, &p.Height, &p.Weight, &p.IsDeleted, &p.IsBlocked, &p.IsPremium, &p.IsShowDistance,
 		&p.IsInvisible, &p.UpdatedAt, &p.LastOnline, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func Delete, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func Delete, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
+// Restore restores the profile which has been deleted after deletedAfter, false is returned when there is no
+// such profile
+func (r *RepositoryProfile) Restore(
+	ctx context.Context, profileID uint64, deletedAfter time.Time, updatedAt time.Time) (bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "Restore", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.Restore")
+	defer span.End()
+	query := `UPDATE profiles SET is_deleted=false, deleted_at=NULL, updated_at=$3
+			  WHERE id=$1 AND is_deleted=true AND erased_at IS NULL AND deleted_at > $2`
+	result, err := r.db.ExecContext(ctx, query, profileID, deletedAfter, updatedAt)
+	if err != nil {
+		r.logger.With(ctx).Error("error func Restore, method ExecContext", zap.Error(err))
+		return false, psqlRepo.MapError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		r.logger.With(ctx).Error("error func Restore, method RowsAffected", zap.Error(err))
+		return false, psqlRepo.MapError(err)
+	}
+	return count > 0, nil
+}
+
+func (r *RepositoryProfile) SelectListDeletedBefore(
+	ctx context.Context, moment time.Time) ([]*profile.DeletedProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListDeletedBefore", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListDeletedBefore")
+	defer span.End()
+	query := `SELECT id, session_id, deleted_at
+			  FROM profiles
+			  WHERE is_deleted=true AND erased_at IS NULL AND deleted_at < $1`
+	rows, err := r.db.QueryContext(ctx, query, moment)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListDeletedBefore, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.DeletedProfile, 0)
+	for rows.Next() {
+		p := profile.DeletedProfile{}
+		if err := rows.Scan(&p.ID, &p.SessionID, &p.DeletedAt); err != nil {
+			r.logger.With(ctx).Error("error func SelectListDeletedBefore, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// Erase removes the rows of the profile and the rows of the other profiles about it from the profile_* tables.
+// The payments are kept for accounting, so the profile row stays as a tombstone without personal data.
+// The tombstone is marked with erasedAt, so it is not selected by SelectListDeletedBefore again.
+// The number of removed rows by table is returned.
+func (r *RepositoryProfile) Erase(
+	ctx context.Context, profileID uint64, erasedAt time.Time) (map[string]int64, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "Erase", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.Erase")
+	defer span.End()
+	statements := []struct {
+		table string
+		query string
+	}{
+		{"profile_review_reports", `DELETE FROM profile_review_reports WHERE profile_id=$1 OR review_id IN
+			(SELECT id FROM profile_reviews WHERE profile_id=$1 OR human_id=$1)`},
+		{"profile_reviews", `DELETE FROM profile_reviews WHERE profile_id=$1 OR human_id=$1`},
+		{"profile_likes", `DELETE FROM profile_likes WHERE profile_id=$1 OR human_id=$1`},
+		{"profile_swipes", `DELETE FROM profile_swipes WHERE profile_id=$1 OR human_id=$1`},
+		{"profile_blocks", `DELETE FROM profile_blocks WHERE profile_id=$1 OR blocked_user_id=$1`},
+		{"profile_complaints", `DELETE FROM profile_complaints WHERE profile_id=$1 OR complaint_user_id=$1`},
+		{"profile_visits", `DELETE FROM profile_visits WHERE profile_id=$1 OR viewer_id=$1`},
+		{"profile_images", `DELETE FROM profile_images WHERE profile_id=$1`},
+		{"profile_telegram", `DELETE FROM profile_telegram WHERE profile_id=$1`},
+		{"profile_navigators", `DELETE FROM profile_navigators WHERE profile_id=$1`},
+		{"profile_filters", `DELETE FROM profile_filters WHERE profile_id=$1`},
+		{"profile_subscriptions", `DELETE FROM profile_subscriptions WHERE profile_id=$1`},
+		{"profile_quota_usages", `DELETE FROM profile_quota_usages WHERE profile_id=$1`},
+		{"profile_exports", `DELETE FROM profile_exports WHERE profile_id=$1`},
+		{"profile_age_verifications", `DELETE FROM profile_age_verifications WHERE profile_id=$1`},
+		{"profile_selfie_verifications", `DELETE FROM profile_selfie_verifications WHERE profile_id=$1`},
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func Erase, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	removed := make(map[string]int64, len(statements))
+	for _, st := range statements {
+		result, err := tx.ExecContext(ctx, st.query, profileID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func Erase, method ExecContext",
+				zap.String("table", st.table), zap.Error(err))
+			return nil, psqlRepo.MapError(err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			r.logger.With(ctx).Error("error func Erase, method RowsAffected", zap.Error(err))
+			return nil, psqlRepo.MapError(err)
+		}
+		removed[st.table] = count
+	}
+	// The erased tombstone has no birthday, so it is skipped by FindById, FindBySessionID and FindByTelegramId
+	query := `UPDATE profiles SET session_id='', display_name='', birthday=NULL, gender='', location='',
+			  description='', height=0, weight=0, is_premium=false, is_invisible=false, erased_at=$2
+			  WHERE id=$1`
+	result, err := tx.ExecContext(ctx, query, profileID, erasedAt)
+	if err != nil {
+		r.logger.With(ctx).Error("error func Erase, method ExecContext",
+			zap.String("table", "profiles"), zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		r.logger.With(ctx).Error("error func Erase, method RowsAffected", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	removed["profiles"] = count
+	if err := tx.Commit(); err != nil {
+		r.logger.With(ctx).Error("error func Erase, method Commit", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return removed, nil
+}
+
+func (r *RepositoryProfile) AddDeletionAudit(
+	ctx context.Context, p *profile.DeletionAuditProfile) (*profile.DeletionAuditProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddDeletionAudit", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddDeletionAudit")
+	defer span.End()
+	removedRows, err := json.Marshal(p.RemovedRows)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddDeletionAudit, method Marshal", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	query := `INSERT INTO profile_deletion_audits
+			  (profile_id, deleted_at, erased_at, removed_rows, removed_files, is_identity_removed, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id`
+	err = r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.DeletedAt, &p.ErasedAt, removedRows, &p.RemovedFiles,
+		&p.IsIdentityRemoved, &p.CreatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddDeletionAudit, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
 func (r *RepositoryProfile) FindById(ctx context.Context, id uint64) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindById", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindById")
+	defer span.End()
 	p := profile.Profile{}
 	query := `SELECT id, session_id, display_name, birthday, gender, location, description, height, weight,
-       is_deleted, is_blocked, is_premium, is_show_distance, is_invisible, created_at, updated_at, last_online
+       is_deleted, is_blocked, is_premium, is_show_distance, is_invisible, created_at, updated_at, last_online,
+       ` + verifiedCondition("profiles.id") + `
 			  FROM profiles
-			  WHERE id = $1`
+			  WHERE id = $1 AND erased_at IS NULL`
 	row := r.db.QueryRowContext(ctx, query, id)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindById, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindById, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.SessionID, &p.DisplayName, &p.Birthday, &p.Gender, &p.Location,
 		&p.Description, &p.Height, &p.Weight, &p.IsDeleted, &p.IsBlocked, &p.IsPremium,
-		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline)
+		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline, &p.IsVerified)
 	if err != nil {
-		r.logger.Debug("error func FindById, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindById, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) FindBySessionID(ctx context.Context, sessionID string) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindBySessionID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindBySessionID")
+	defer span.End()
 	p := profile.Profile{}
 	query := `SELECT id, session_id, display_name, birthday, gender, location, description, height, weight,
-       is_deleted, is_blocked, is_premium, is_show_distance, is_invisible, created_at, updated_at, last_online
+       is_deleted, is_blocked, is_premium, is_show_distance, is_invisible, created_at, updated_at, last_online,
+       ` + verifiedCondition("profiles.id") + `
 			  FROM profiles
-			  WHERE session_id=$1`
+			  WHERE session_id=$1 AND erased_at IS NULL`
 	row := r.db.QueryRowContext(ctx, query, sessionID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindBySessionID, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindBySessionID, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.SessionID, &p.DisplayName, &p.Birthday, &p.Gender, &p.Location,
 		&p.Description, &p.Height, &p.Weight, &p.IsDeleted, &p.IsBlocked, &p.IsPremium,
-		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline)
+		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline, &p.IsVerified)
 	if err != nil {
-		r.logger.Debug("error func FindBySessionID, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindBySessionID, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) FindByTelegramId(ctx context.Context, telegramID uint64) (*profile.Profile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindByTelegramId", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindByTelegramId")
+	defer span.End()
 	p := profile.Profile{}
 	query := `SELECT p.id, p.session_id, p.display_name, p.birthday, p.gender, p.location,
        p.description, p.height, p.weight, p.is_deleted, p.is_blocked, p.is_premium, p.is_show_distance,
-       p.is_invisible, p.created_at, p.updated_at,  p.last_online
+       p.is_invisible, p.created_at, p.updated_at,  p.last_online, ` + verifiedCondition("p.id") + `
 			  FROM profiles p
 			  JOIN profile_telegram pt ON p.id = pt.profile_id
-			  WHERE pt.telegram_id = $1`
+			  WHERE pt.telegram_id = $1 AND p.erased_at IS NULL`
 	row := r.db.QueryRowContext(ctx, query, telegramID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindByTelegramId, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindByTelegramId, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.SessionID, &p.DisplayName, &p.Birthday, &p.Gender, &p.Location,
 		&p.Description, &p.Height, &p.Weight, &p.IsDeleted, &p.IsBlocked, &p.IsPremium,
-		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline)
+		&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline, &p.IsVerified)
 	if err != nil {
-		r.logger.Debug("error func FindByTelegramId, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindByTelegramId, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
-func (r *RepositoryProfile) SelectList(
-	ctx context.Context, qp *profile.QueryParamsProfileList) (*profile.ResponseListProfile, error) {
+// SelectListCandidate returns at most limit candidates of the feed which have been online the latest, with their
+// exact distances to the viewer. The signals of the ranker are selected for the returned candidates only.
+func (r *RepositoryProfile) SelectListCandidate(ctx context.Context, qp *profile.QueryParamsProfileList,
+	limit uint64) ([]*profile.CandidateProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListCandidate", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListCandidate")
+	defer span.End()
 	p, err := r.FindBySessionID(ctx, qp.SessionID)
 	if err != nil {
-		r.logger.Debug("error func SelectList, method FindBySessionID by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectListCandidate, method FindBySessionID", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	ageFromInt, err := strconv.Atoi(qp.AgeFrom)
 	if err != nil {
-		return nil, err
+		return nil, psqlRepo.MapError(err)
 	}
 	ageToInt, err := strconv.Atoi(qp.AgeTo)
 	if err != nil {
-		return nil, err
+		return nil, psqlRepo.MapError(err)
 	}
 	birthYearStart := time.Now().UTC().Year() - ageToInt - 1
 	birthYearEnd := time.Now().UTC().Year() - ageFromInt
 	birthdateFrom := time.Date(birthYearStart, time.January, 1, 0, 0, 0, 0, time.UTC)
 	birthdateTo := time.Date(birthYearEnd, time.December, 31, 23, 59, 59, 999999999, time.UTC)
-	// Convert qp.Distance from kilometers to meters
-	distanceMeters, err := strconv.ParseFloat(qp.Distance, 64)
-	if err != nil {
-		r.logger.Debug("error func SelectList, method ParseFloat by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
-	}
-	distanceMeters *= 1000 // Convert kilometers to meters
-	query := "SELECT p.id, p.session_id, p.display_name, p.birthday, p.gender, p.location," +
-		" p.description, p.height, p.weight, p.is_deleted, p.is_blocked, p.is_premium," +
-		" p.is_show_distance, p.is_invisible, p.created_at, p.updated_at, p.last_online," +
-		" ST_Distance((SELECT location FROM profile_navigators WHERE profile_id = p.id)::geography, " +
-		" ST_SetSRID(ST_MakePoint((SELECT ST_X(location) FROM profile_navigators WHERE profile_id = $4), " +
-		" (SELECT ST_Y(location) FROM profile_navigators WHERE profile_id = $4)),  4326)::geography) as distance" +
-		" FROM profiles p" +
+	radius, err := profile.GetFeedRadius(qp.Distance)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListCandidate, method GetFeedRadius", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	// The noisy distance is within the radius only when the exact one is within this bound, the noisy distance
+	// itself is filtered by the use case
+	distanceBound := radius / (1 - profile.DistanceNoiseRatio)
+	// Reciprocal mode shows only candidates whose own filters match the viewer.
+	// Only premium profiles are allowed to switch it off.
+	isReciprocal := !(qp.IsReciprocalDisabled && p.IsPremium)
+	viewerAge := profile.GetAge(p.Birthday, time.Now().UTC())
+	// The minors are never shown, even when the requested age range starts below profile.MinAge
+	adultBirthdateTo := time.Now().UTC().AddDate(-profile.MinAge, 0, 0)
+	conditions := " FROM profiles p" +
 		" JOIN profile_navigators pn ON p.id = pn.profile_id" +
+		" CROSS JOIN LATERAL (SELECT ST_Distance(pn.location::geography," +
+		" ST_SetSRID(ST_MakePoint((SELECT ST_X(location) FROM profile_navigators WHERE profile_id = $4)," +
+		" (SELECT ST_Y(location) FROM profile_navigators WHERE profile_id = $4)), 4326)::geography)" +
+		" AS exact_distance) d" +
+		" LEFT JOIN profile_filters pf ON p.id = pf.profile_id" +
 		" WHERE p.is_deleted=false AND  p.is_blocked=false AND  p.birthday BETWEEN $1 AND $2" +
 		" AND ($3 = 'all' OR gender=$3) AND  p.id <> $4 AND" +
-		" NOT EXISTS (SELECT 1 FROM profile_blocks WHERE profile_id = $4 AND blocked_user_id = p.id) AND" +
-		" ST_Distance((SELECT location FROM profile_navigators WHERE profile_id = p.id)::geography, " +
-		" ST_SetSRID(ST_MakePoint((SELECT ST_X(location) FROM profile_navigators WHERE profile_id = $4), " +
-		" (SELECT ST_Y(location) FROM profile_navigators WHERE profile_id = $4)), 4326)::geography) <= $5" +
-		" ORDER BY distance ASC, p.last_online DESC"
-	countQuery := "SELECT COUNT(*) FROM profiles WHERE is_deleted=false AND is_blocked=false AND birthday BETWEEN $1" +
-		" AND $2 AND ($3 = 'all' OR gender=$3) AND id <> $4"
-	size := qp.Size
-	page := qp.Page
-	// get totalItems
-	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, birthdateFrom, birthdateTo, qp.SearchGender,
-		p.ID)
-	if err != nil {
-		r.logger.Debug("error func SelectList, method GetTotalItems by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
-	}
-	// pagination
-	query = pagination.ApplyPagination(query, page, size)
-	countQuery = pagination.ApplyPagination(countQuery, page, size)
-	// get navigator by profile id
-	queryParams := []interface{}{birthdateFrom, birthdateTo, qp.SearchGender, p.ID, distanceMeters}
-	rows, err := r.db.QueryContext(ctx, query, queryParams...)
-	if err != nil {
-		r.logger.Debug("error func SelectList, method QueryContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		" NOT EXISTS (SELECT 1 FROM profile_blocks pb WHERE pb.is_blocked=true AND" +
+		" ((pb.profile_id = $4 AND pb.blocked_user_id = p.id) OR (pb.profile_id = p.id AND pb.blocked_user_id = $4)))" +
+		" AND" +
+		" NOT EXISTS (SELECT 1 FROM profile_likes WHERE profile_id = $4 AND human_id = p.id AND is_liked=true) AND" +
+		" (p.is_invisible=false OR EXISTS (SELECT 1 FROM profile_likes" +
+		" WHERE profile_id = p.id AND human_id = $4 AND is_liked=true)) AND" +
+		" NOT EXISTS (SELECT 1 FROM profile_swipes ps WHERE ps.profile_id = $4 AND ps.human_id = p.id" +
+		" AND ps.is_rewound=false AND (ps.expires_at IS NULL OR ps.expires_at > NOW() AT TIME ZONE 'UTC')) AND" +
+		" NOT EXISTS (SELECT 1 FROM profile_age_verifications pav WHERE pav.profile_id = p.id" +
+		" AND pav.status='pending') AND p.birthday <= $9 AND" +
+		" ($10 = false OR " + verifiedCondition("p.id") + ") AND" +
+		" d.exact_distance <= $5 AND " + reciprocalCondition("$6", "$7", "$8", "$4")
+	queryParams := []interface{}{birthdateFrom, birthdateTo, qp.SearchGender, p.ID, distanceBound, isReciprocal,
+		p.Gender, viewerAge, adultBirthdateTo, qp.IsVerified}
+	// The pool is bounded before the correlated subqueries of the ranker signals are run. It is picked by the last
+	// online time, because its membership would reveal the exact distance if it was picked by the distance.
+	query := "WITH pool AS (SELECT p.id, p.session_id, p.display_name, p.birthday, p.gender, p.location," +
+		" p.description, p.height, p.weight, p.is_deleted, p.is_blocked, p.is_premium," +
+		" p.is_show_distance, p.is_invisible, p.created_at, p.updated_at, p.last_online," +
+		" d.exact_distance as distance" +
+		conditions +
+		" ORDER BY p.last_online DESC, p.id ASC LIMIT $11)" +
+		" SELECT p.id, p.session_id, p.display_name, p.birthday, p.gender, p.location," +
+		" p.description, p.height, p.weight, p.is_deleted, p.is_blocked, p.is_premium," +
+		" p.is_show_distance, p.is_invisible, p.created_at, p.updated_at, p.last_online, " +
+		verifiedCondition("p.id") + ", p.distance," +
+		" (SELECT COUNT(*) FROM profile_images pi WHERE pi.profile_id = p.id AND pi.is_deleted=false" +
+		" AND pi.is_blocked=false AND pi.is_private=false) as count_images," +
+		" (SELECT COUNT(*) FROM profile_likes pl WHERE pl.human_id = p.id AND pl.is_liked=true)" +
+		" as count_likes_received," +
+		" (SELECT COUNT(*) FROM profile_likes pl WHERE pl.human_id = p.id AND pl.is_liked=true AND" +
+		" EXISTS (SELECT 1 FROM profile_likes plb WHERE plb.profile_id = p.id AND plb.human_id = pl.profile_id" +
+		" AND plb.is_liked=true)) as count_likes_returned," +
+		" EXISTS (SELECT 1 FROM profile_likes pl WHERE pl.profile_id = p.id AND pl.human_id = $4" +
+		" AND pl.is_liked=true) as is_liked_viewer" +
+		" FROM pool p" +
+		" ORDER BY p.last_online DESC, p.id ASC"
+	rows, err := r.db.QueryContext(ctx, query, append(queryParams, limit)...)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListCandidate, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer rows.Close()
-	list := make([]*profile.ContentListProfile, 0)
+	list := make([]*profile.CandidateProfile, 0)
 	for rows.Next() {
 		p := profile.Profile{}
-		n := &profile.ResponseNavigatorProfile{}
+		c := profile.CandidateProfile{}
 		err := rows.Scan(&p.ID, &p.SessionID, &p.DisplayName, &p.Birthday, &p.Gender, &p.Location,
 			&p.Description, &p.Height, &p.Weight, &p.IsDeleted, &p.IsBlocked, &p.IsPremium,
-			&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline, &n.Distance)
-		if err != nil {
-			r.logger.Debug("error func SelectList, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-				zap.Error(err))
-			continue
-		}
-		images, err := r.SelectListPublicImage(ctx, p.ID)
+			&p.IsShowDistance, &p.IsInvisible, &p.CreatedAt, &p.UpdatedAt, &p.LastOnline, &p.IsVerified, &c.Distance,
+			&c.CountImages, &c.CountLikesReceived, &c.CountLikesReturned, &c.IsLikedViewer)
 		if err != nil {
-			r.logger.Debug("error func SelectList, method SelectListPublicImage by path"+
-				" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+			r.logger.With(ctx).Error("error func SelectListCandidate, method Scan", zap.Error(err))
 			continue
 		}
-		lp := profile.ContentListProfile{
-			ID:         p.ID,
-			IsOnline:   false,
-			LastOnline: p.LastOnline,
-			Image:      nil,
-			Navigator:  n,
-		}
-		elapsed := time.Since(p.LastOnline)
-		if elapsed.Minutes() < 5 {
-			lp.IsOnline = true
-		}
-		if len(images) > 0 {
-			i := profile.ResponseImageProfile{
-				Url: images[0].Url,
-			}
-			lp.Image = &i
-		}
-		list = append(list, &lp)
-	}
-	paging := pagination.GetPagination(size, page, totalItems)
-	response := profile.ResponseListProfile{
-		Pagination: paging,
-		Content:    list,
+		c.Profile = &p
+		list = append(list, &c)
 	}
-	return &response, nil
+	return list, nil
+}
+
+// verifiedCondition returns the SQL condition under which the profile has the approved selfie
+func verifiedCondition(profileID string) string {
+	return "EXISTS (SELECT 1 FROM profile_selfie_verifications psv WHERE psv.profile_id = " + profileID +
+		" AND psv.status='approved')"
+}
+
+// reciprocalCondition returns the SQL condition under which the viewer satisfies the candidate's own filter:
+// search gender, age range and distance. Zero values in profile_filters mean that the limit is not set.
+// The candidate profile must be aliased as "p" and its filter as "pf".
+func reciprocalCondition(isReciprocal, viewerGender, viewerAge, viewerID string) string {
+	return "(" + isReciprocal + " = false OR (" +
+		"(pf.id IS NULL OR COALESCE(pf.search_gender, '') IN ('', 'all') OR pf.search_gender = " + viewerGender + ")" +
+		" AND (COALESCE(pf.age_from, 0) = 0 OR " + viewerAge + " >= pf.age_from)" +
+		" AND (COALESCE(pf.age_to, 0) = 0 OR " + viewerAge + " <= pf.age_to)" +
+		" AND (COALESCE(pf.distance, 0) = 0 OR" +
+		" ST_Distance((SELECT location FROM profile_navigators WHERE profile_id = p.id)::geography," +
+		" (SELECT location FROM profile_navigators WHERE profile_id = " + viewerID + ")::geography)" +
+		" <= pf.distance * 1000)))"
 }
 
 func (r *RepositoryProfile) AddTelegram(
 	ctx context.Context, p *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddTelegram", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddTelegram")
+	defer span.End()
 	query := "INSERT INTO profile_telegram (profile_id, telegram_id, username, first_name, last_name, language_code," +
 		" allows_write_to_pm, query_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id"
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.TelegramID, &p.UserName, &p.Firstname, &p.Lastname,
 		&p.LanguageCode, &p.AllowsWriteToPm, &p.QueryID).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug(
-			"error func AddTelegram, method QueryRowContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddTelegram, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateTelegram(
 	ctx context.Context, p *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateTelegram", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateTelegram")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateTelegram, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateTelegram, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_telegram SET username=$1, first_name=$2, last_name=$3, language_code=$4," +
@@ -323,9 +510,8 @@ func (r *RepositoryProfile) UpdateTelegram(
 	_, err = r.db.ExecContext(ctx, query, &p.UserName, &p.Firstname, &p.Lastname, &p.LanguageCode, &p.AllowsWriteToPm,
 		&p.ID)
 	if err != nil {
-		r.logger.Debug("error func UpdateTelegram, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateTelegram, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -333,11 +519,13 @@ func (r *RepositoryProfile) UpdateTelegram(
 
 func (r *RepositoryProfile) DeleteTelegram(
 	ctx context.Context, p *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteTelegram", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteTelegram")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func DeleteTelegram, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteTelegram, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_telegram SET telegram_id=$1, username=$2, first_name=$3, last_name=$4, language_code=$5," +
@@ -345,9 +533,8 @@ func (r *RepositoryProfile) DeleteTelegram(
 	_, err = r.db.ExecContext(ctx, query, &p.TelegramID, &p.UserName, &p.Firstname, &p.Lastname, &p.LanguageCode,
 		&p.AllowsWriteToPm, &p.QueryID, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func DeleteTelegram, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteTelegram, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -355,6 +542,9 @@ func (r *RepositoryProfile) DeleteTelegram(
 
 func (r *RepositoryProfile) FindTelegramByProfileID(
 	ctx context.Context, profileID uint64) (*profile.TelegramProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindTelegramByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindTelegramByProfileID")
+	defer span.End()
 	p := profile.TelegramProfile{}
 	query := `SELECT id, profile_id, telegram_id, username, first_name, last_name, language_code, allows_write_to_pm,
        query_id
@@ -363,48 +553,51 @@ func (r *RepositoryProfile) FindTelegramByProfileID(
 	row := r.db.QueryRowContext(ctx, query, profileID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindTelegramByProfileID, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindTelegramByProfileID, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.ProfileID, &p.TelegramID, &p.UserName, &p.Firstname, &p.Lastname, &p.LanguageCode,
 		&p.AllowsWriteToPm, &p.QueryID)
 	if err != nil {
-		r.logger.Debug("error func FindTelegramByProfileID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindTelegramByProfileID, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) AddNavigator(
 	ctx context.Context, p *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddNavigator", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddNavigator")
+	defer span.End()
 	query := "INSERT INTO profile_navigators (profile_id, location)" +
 		" VALUES ($1, ST_SetSRID(ST_MakePoint($2, $3),  4326)) RETURNING id"
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Location.Longitude, &p.Location.Latitude).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func AddNavigator, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddNavigator, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateNavigator(
 	ctx context.Context, p *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateNavigator", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateNavigator")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateNavigator, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateNavigator, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
-	query := "UPDATE profile_navigators SET location=ST_SetSRID(ST_MakePoint($1, $2),  4326) WHERE profile_id=$3"
-	_, err = r.db.ExecContext(ctx, query, &p.Location.Longitude, &p.Location.Latitude, &p.ProfileID)
+	query := "UPDATE profile_navigators SET location=ST_SetSRID(ST_MakePoint($1, $2),  4326), moved_at=$4," +
+		" jump_count=$5, jumps_started_at=$6 WHERE profile_id=$3"
+	_, err = r.db.ExecContext(ctx, query, &p.Location.Longitude, &p.Location.Latitude, &p.ProfileID, p.MovedAt,
+		p.JumpCount, p.JumpsStartedAt)
 	if err != nil {
-		r.logger.Debug("error func UpdateNavigator, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateNavigator, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -412,20 +605,20 @@ func (r *RepositoryProfile) UpdateNavigator(
 
 func (r *RepositoryProfile) DeleteNavigator(
 	ctx context.Context, p *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteNavigator", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteNavigator")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func DeleteNavigator, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteNavigator, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_navigators SET location=ST_SetSRID(ST_MakePoint($1, $2),  4326) WHERE id=$3"
 	_, err = r.db.ExecContext(ctx, query, &p.Location.Longitude, &p.Location.Latitude, &p.ID)
 	if err != nil {
-		r.logger.Debug(
-			"error func DeleteNavigator, method QueryRowContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteNavigator, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -433,28 +626,29 @@ func (r *RepositoryProfile) DeleteNavigator(
 
 func (r *RepositoryProfile) FindNavigatorByProfileID(
 	ctx context.Context, profileID uint64) (*profile.NavigatorProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindNavigatorByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindNavigatorByProfileID")
+	defer span.End()
 	p := profile.NavigatorProfile{}
 	var longitude sql.NullFloat64
 	var latitude sql.NullFloat64
-	query := `SELECT id, profile_id, ST_X(location) as longitude, ST_Y(location) as latitude
+	query := `SELECT id, profile_id, ST_X(location) as longitude, ST_Y(location) as latitude, moved_at, jump_count,
+       jumps_started_at
 			  FROM profile_navigators
 			  WHERE profile_id = $1`
 	row := r.db.QueryRowContext(ctx, query, profileID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug(
-			"error func FindNavigatorById, method QueryRowContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorById, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
-	err := row.Scan(&p.ID, &p.ProfileID, &longitude, &latitude)
+	err := row.Scan(&p.ID, &p.ProfileID, &longitude, &latitude, &p.MovedAt, &p.JumpCount, &p.JumpsStartedAt)
 	if err != nil {
-		r.logger.Debug("error func FindNavigatorById, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorById, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	if !longitude.Valid && !latitude.Valid {
-		return nil, err
+		return nil, psqlRepo.MapError(err)
 	}
 	p.Location = &profile.Point{
 		Latitude:  latitude.Float64,
@@ -465,19 +659,22 @@ func (r *RepositoryProfile) FindNavigatorByProfileID(
 
 func (r *RepositoryProfile) FindNavigatorByProfileIDAndViewerID(
 	ctx context.Context, profileID uint64, viewerID uint64) (*profile.ResponseNavigatorProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindNavigatorByProfileIDAndViewerID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindNavigatorByProfileIDAndViewerID")
+	defer span.End()
 	// Get coordinates for viewerID
 	vn, err := r.FindNavigatorByProfileID(ctx, viewerID)
 	if err != nil {
-		r.logger.Debug("error func FindNavigatorByProfileIDAndViewerID, method FindNavigatorByProfileID by path"+
-			" internal/handler/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerID,"+
+			" method FindNavigatorByProfileID", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	// Get coordinates for profileID
 	pn, err := r.FindNavigatorByProfileID(ctx, profileID)
 	if err != nil {
-		r.logger.Debug("error func FindNavigatorByProfileIDAndViewerID, method FindNavigatorByProfileID by path"+
-			" internal/handler/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerID,"+
+			" method FindNavigatorByProfileID", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	p := profile.NavigatorProfile{}
 	var longitude sql.NullFloat64
@@ -494,43 +691,46 @@ func (r *RepositoryProfile) FindNavigatorByProfileIDAndViewerID(
 		pn.Location.Latitude, profileID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindNavigatorByProfileIDAndViewerID, method QueryRowContext by path "+
-			"internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerID, method QueryRowContext",
+			zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err = row.Scan(&p.ID, &p.ProfileID, &longitude, &latitude, &distance)
 	if err != nil {
-		r.logger.Debug("error func FindNavigatorByProfileIDAndViewerID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerID, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	response := &profile.ResponseNavigatorProfile{
-		Distance: distance.Float64,
+		Distance: &distance.Float64,
 	}
 	return response, nil
 }
 
 func (r *RepositoryProfile) AddFilter(
 	ctx context.Context, p *profile.FilterProfile) (*profile.FilterProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddFilter", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddFilter")
+	defer span.End()
 	query := "INSERT INTO profile_filters (profile_id, search_gender, looking_for, age_from, age_to, distance, page," +
 		" size) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id"
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.SearchGender, &p.LookingFor, &p.AgeFrom, &p.AgeTo,
 		&p.Distance, &p.Page, &p.Size).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func AddFilter, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddFilter, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateFilter(
 	ctx context.Context, p *profile.FilterProfile) (*profile.FilterProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateFilter", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateFilter")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateFilter, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateFilter, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_filters SET search_gender=$1, looking_for=$2, age_from=$3, age_to=$4, distance=$5," +
@@ -538,9 +738,8 @@ func (r *RepositoryProfile) UpdateFilter(
 	_, err = r.db.ExecContext(ctx, query, &p.SearchGender, &p.LookingFor, &p.AgeFrom, &p.AgeTo,
 		&p.Distance, &p.Page, &p.Size, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func UpdateFilter, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateFilter, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -548,11 +747,13 @@ func (r *RepositoryProfile) UpdateFilter(
 
 func (r *RepositoryProfile) DeleteFilter(
 	ctx context.Context, p *profile.FilterProfile) (*profile.FilterProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteFilter", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteFilter")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func DeleteFilter, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteFilter, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_filters SET search_gender=$1, looking_for=$2, age_from=$3, age_to=$4, distance=$5," +
@@ -560,9 +761,8 @@ func (r *RepositoryProfile) DeleteFilter(
 	_, err = r.db.ExecContext(ctx, query, &p.SearchGender, &p.LookingFor, &p.AgeFrom, &p.AgeTo,
 		&p.Distance, &p.Page, &p.Size, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func DeleteFilter, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteFilter, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -570,6 +770,9 @@ func (r *RepositoryProfile) DeleteFilter(
 
 func (r *RepositoryProfile) FindFilterByProfileID(
 	ctx context.Context, profileID uint64) (*profile.FilterProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindFilterByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindFilterByProfileID")
+	defer span.End()
 	p := profile.FilterProfile{}
 	query := `SELECT id, profile_id, search_gender, looking_for, age_from, age_to, distance, page, size
 			  FROM profile_filters
@@ -577,40 +780,41 @@ func (r *RepositoryProfile) FindFilterByProfileID(
 	row := r.db.QueryRowContext(ctx, query, profileID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindFilterByProfileID, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindFilterByProfileID, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.ProfileID, &p.SearchGender, &p.LookingFor, &p.AgeFrom, &p.AgeTo,
 		&p.Distance, &p.Page, &p.Size)
 	if err != nil {
-		r.logger.Debug("error func FindFilterByProfileID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindFilterByProfileID, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) AddImage(ctx context.Context, p *profile.ImageProfile) (*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddImage")
+	defer span.End()
 	query := "INSERT INTO profile_images (profile_id, name, url, size, created_at, updated_at, is_deleted," +
 		" is_blocked, is_primary, is_private) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Name, &p.Url, &p.Size, &p.CreatedAt, &p.UpdatedAt,
 		&p.IsDeleted, &p.IsBlocked, &p.IsPrimary, &p.IsPrivate).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug(
-			"error func AddImage, method QueryRowContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddImage, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateImage(ctx context.Context, p *profile.ImageProfile) (*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateImage")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateImage, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateImage, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_images SET name=$1, url=$2, size=$3, updated_at=$4, is_deleted=$5, is_blocked=$6," +
@@ -618,35 +822,37 @@ func (r *RepositoryProfile) UpdateImage(ctx context.Context, p *profile.ImagePro
 	_, err = r.db.ExecContext(ctx, query, &p.Name, &p.Url, &p.Size, &p.UpdatedAt, &p.IsDeleted, &p.IsBlocked,
 		&p.IsPrimary, &p.IsPrivate, &p.ID)
 	if err != nil {
-		r.logger.Debug(
-			"error func UpdateImage method QueryRowContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateImage method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) DeleteImage(ctx context.Context, p *profile.ImageProfile) (*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteImage")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func DeleteImage, method Begin by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteImage, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_images SET is_deleted=$1 WHERE id=$2"
 	_, err = r.db.ExecContext(ctx, query, &p.IsDeleted, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func DeleteImage method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteImage method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) FindImageById(ctx context.Context, imageID uint64) (*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindImageById", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindImageById")
+	defer span.End()
 	p := profile.ImageProfile{}
 	query := `SELECT id, profile_id, name, url, size, created_at, updated_at, is_deleted, is_blocked, is_primary,
        is_private
@@ -655,31 +861,32 @@ func (r *RepositoryProfile) FindImageById(ctx context.Context, imageID uint64) (
 	row := r.db.QueryRowContext(ctx, query, imageID)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindImageById, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindImageById, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	err := row.Scan(&p.ID, &p.ProfileID, &p.Name, &p.Url, &p.Size, &p.CreatedAt, &p.UpdatedAt,
 		&p.IsDeleted, &p.IsBlocked, &p.IsPrimary, &p.IsPrivate)
 	if err != nil {
-		r.logger.Debug("error func FindImageById, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindImageById, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) SelectListPublicImage(
 	ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListPublicImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListPublicImage")
+	defer span.End()
 	query := `SELECT id, profile_id, name, url, size, created_at, updated_at, is_deleted, is_blocked, is_primary,
        is_private
 	FROM profile_images
 	WHERE profile_id=$1 AND is_deleted=false AND is_blocked=false AND is_private=false`
 	rows, err := r.db.QueryContext(ctx, query, profileID)
 	if err != nil {
-		r.logger.Debug("error func SelectListPublicImage,"+
-			" method QueryContext by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectListPublicImage,"+
+			" method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer rows.Close()
 	list := make([]*profile.ImageProfile, 0)
@@ -688,8 +895,8 @@ func (r *RepositoryProfile) SelectListPublicImage(
 		err := rows.Scan(&p.ID, &p.ProfileID, &p.Name, &p.Url, &p.Size, &p.CreatedAt, &p.UpdatedAt, &p.IsDeleted,
 			&p.IsBlocked, &p.IsPrimary, &p.IsPrivate)
 		if err != nil {
-			r.logger.Debug("error func SelectListPublicImage,"+
-				" method Scan by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+			r.logger.With(ctx).Error("error func SelectListPublicImage,"+
+				" method Scan", zap.Error(err))
 			continue
 		}
 		list = append(list, &p)
@@ -697,17 +904,57 @@ func (r *RepositoryProfile) SelectListPublicImage(
 	return list, nil
 }
 
+// SelectListFirstPublicImage returns the primary or else the oldest public image of every profile by its id
+func (r *RepositoryProfile) SelectListFirstPublicImage(
+	ctx context.Context, profileIDs []uint64) (map[uint64]*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListFirstPublicImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListFirstPublicImage")
+	defer span.End()
+	ids := make([]int64, 0, len(profileIDs))
+	for _, id := range profileIDs {
+		ids = append(ids, int64(id))
+	}
+	query := `SELECT DISTINCT ON (profile_id) id, profile_id, name, url, size, created_at, updated_at, is_deleted,
+       is_blocked, is_primary, is_private
+	FROM profile_images
+	WHERE profile_id = ANY($1) AND is_deleted=false AND is_blocked=false AND is_private=false
+	ORDER BY profile_id, is_primary DESC, id`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListFirstPublicImage,"+
+			" method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	images := make(map[uint64]*profile.ImageProfile, len(profileIDs))
+	for rows.Next() {
+		p := profile.ImageProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Name, &p.Url, &p.Size, &p.CreatedAt, &p.UpdatedAt, &p.IsDeleted,
+			&p.IsBlocked, &p.IsPrimary, &p.IsPrivate)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListFirstPublicImage,"+
+				" method Scan", zap.Error(err))
+			continue
+		}
+		images[p.ProfileID] = &p
+	}
+	return images, nil
+}
+
 func (r *RepositoryProfile) SelectListImage(
 	ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListImage", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListImage")
+	defer span.End()
 	query := `SELECT id, profile_id, name, url, size, created_at, updated_at, is_deleted, is_blocked, is_primary,
        is_private
 	FROM profile_images
 	WHERE profile_id=$1`
 	rows, err := r.db.QueryContext(ctx, query, profileID)
 	if err != nil {
-		r.logger.Debug("error func SelectListImage,"+
-			" method QueryContext by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectListImage,"+
+			" method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer rows.Close()
 	list := make([]*profile.ImageProfile, 0)
@@ -716,8 +963,8 @@ func (r *RepositoryProfile) SelectListImage(
 		err := rows.Scan(&p.ID, &p.ProfileID, &p.Name, &p.Url, &p.Size, &p.CreatedAt, &p.UpdatedAt, &p.IsDeleted,
 			&p.IsBlocked, &p.IsPrimary, &p.IsPrivate)
 		if err != nil {
-			r.logger.Debug("error func SelectListImage,"+
-				" method Scan by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+			r.logger.With(ctx).Error("error func SelectListImage,"+
+				" method Scan", zap.Error(err))
 			continue
 		}
 		list = append(list, &p)
@@ -727,6 +974,9 @@ func (r *RepositoryProfile) SelectListImage(
 
 func (r *RepositoryProfile) CheckIfCommonImageExists(
 	ctx context.Context, profileID uint64, fileName string) (bool, uint64, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "CheckIfCommonImageExists", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.CheckIfCommonImageExists")
+	defer span.End()
 	var imageID uint64
 	query := "SELECT id" +
 		" FROM profile_images WHERE profile_id=$1 AND name=$2"
@@ -735,42 +985,44 @@ func (r *RepositoryProfile) CheckIfCommonImageExists(
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, 0, nil
 		}
-		return false, 0, err
+		return false, 0, psqlRepo.MapError(err)
 	}
 	return true, imageID, nil
 }
 
 func (r *RepositoryProfile) AddReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
-	query := "INSERT INTO profile_reviews (profile_id, message, rating, has_deleted, has_edited," +
-		" created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
-	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Message, &p.Rating, &p.HasDeleted,
-		&p.HasEdited, &p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	defer r.metrics.ObserveQuery(repositoryName, "AddReview", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddReview")
+	defer span.End()
+	query := "INSERT INTO profile_reviews (profile_id, human_id, message, rating, has_deleted, has_edited," +
+		" status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id"
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted,
+		&p.HasEdited, &p.Status, &p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func AddReview, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddReview, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateReview(
 	ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateReview", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateReview")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateReview, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateReview, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_reviews SET profile_id=$1, message=$2, rating=$3, has_deleted=$4," +
-		" has_edited=$5, created_at=$6, updated_at=$7 WHERE id=$8 AND has_deleted=false"
+		" has_edited=$5, status=$6, created_at=$7, updated_at=$8 WHERE id=$9 AND has_deleted=false"
 	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.Message, &p.Rating, &p.HasDeleted,
-		&p.HasEdited, &p.CreatedAt, &p.UpdatedAt, &p.ID)
+		&p.HasEdited, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.ID)
 	if err != nil {
-		r.logger.Debug(
-			"error func UpdateReview, method ExecContext by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateReview, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
@@ -778,124 +1030,133 @@ func (r *RepositoryProfile) UpdateReview(
 
 func (r *RepositoryProfile) DeleteReview(
 	ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteReview", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteReview")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug(
-			"error func DeleteReview, method Begin by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteReview, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := "UPDATE profile_reviews SET profile_id=$1, message=$2, rating=$3, has_deleted=$4," +
-		" has_edited=$5, created_at=$6, updated_at=$7 WHERE id=$8 AND has_deleted=false"
+		" has_edited=$5, status=$6, created_at=$7, updated_at=$8 WHERE id=$9 AND has_deleted=false"
 	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.Message, &p.Rating, &p.HasDeleted,
-		&p.HasEdited, &p.CreatedAt, &p.UpdatedAt, &p.ID)
+		&p.HasEdited, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func DeleteReview, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteReview, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) FindReviewById(ctx context.Context, id uint64) (*profile.ResponseReviewProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindReviewById", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindReviewById")
+	defer span.End()
 	p := profile.ResponseReviewProfile{}
-	query := `SELECT pr.id, pr.profile_id, pr.message, pr.rating, pr.has_deleted, pr.has_edited, pr.created_at,
-       pr.updated_at, p.session_id
+	query := `SELECT pr.id, pr.profile_id, COALESCE(pr.human_id, 0), pr.message, pr.rating, pr.has_deleted,
+       pr.has_edited, pr.status, pr.created_at, pr.updated_at, p.session_id
 			  FROM profile_reviews pr
               JOIN profiles p ON pr.profile_id = p.id
 			  WHERE pr.id = $1`
 	row := r.db.QueryRowContext(ctx, query, id)
 	if row == nil {
 		err := errors.New("no rows found")
-		r.logger.Debug("error func FindReviewById, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindReviewById, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
-	err := row.Scan(&p.ID, &p.ProfileID, &p.Message, &p.Rating, &p.HasDeleted,
-		&p.HasEdited, &p.CreatedAt, &p.UpdatedAt, &p.SessionID)
+	err := row.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted,
+		&p.HasEdited, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.SessionID)
 	if err != nil {
-		r.logger.Debug("error func FindReviewById, method Scan by path internal/adapter/psqlRepo/profile/profile.go",
-			zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func FindReviewById, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return &p, nil
 }
 
 func (r *RepositoryProfile) SelectReviewList(
 	ctx context.Context, qp *profile.QueryParamsReviewList) (*profile.ResponseListReview, error) {
-	query := `SELECT pr.id, pr.profile_id, pr.message, pr.rating, pr.has_deleted, pr.has_edited, pr.created_at,
-                pr.updated_at, p.display_name, p.session_id
+	defer r.metrics.ObserveQuery(repositoryName, "SelectReviewList", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectReviewList")
+	defer span.End()
+	query := `SELECT pr.id, pr.profile_id, pr.human_id, pr.message, pr.rating, pr.has_deleted, pr.has_edited,
+                pr.status, pr.created_at, pr.updated_at, p.display_name, p.session_id
               FROM profile_reviews pr
               JOIN profiles p ON pr.profile_id = p.id
-              WHERE has_deleted=false
+              WHERE pr.has_deleted=false AND pr.human_id=$1 AND pr.status='published'
               ORDER BY pr.created_at DESC`
-	// Query to get number of reviews
+	// Query to get number of published reviews of the profile
 	countQuery := `SELECT COUNT(*) FROM profile_reviews pr
-                     WHERE pr.has_deleted=false`
+                     WHERE pr.has_deleted=false AND pr.human_id=$1 AND pr.status='published'`
 	// Query to get number of reviews on current date by profile id
 	countReviewsOnCurrentDateByProfileID := `
 					SELECT COUNT(*)
 					FROM profile_reviews pr
-                    WHERE pr.profile_id=$1 AND pr.created_at::date = CURRENT_DATE`
+                    WHERE pr.profile_id=$1 AND pr.created_at::date = (NOW() AT TIME ZONE 'UTC')::date`
 	profileID, err := strconv.ParseUint(qp.ProfileID, 10, 64)
 	if err != nil {
-		r.logger.Debug("error func SelectReviewList, method ParseUint by path"+
-			" internal/handler/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectReviewList, method ParseUint", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	humanID, err := strconv.ParseUint(qp.HumanID, 10, 64)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectReviewList, method ParseUint humanID", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	var count uint
 	err = r.db.QueryRowContext(ctx, countReviewsOnCurrentDateByProfileID, profileID).Scan(&count)
 	if err != nil {
-		r.logger.Debug("error func SelectReviewList, method QueryRowContext for avgRating by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectReviewList, method QueryRowContext for avgRating", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
-	// Query to get average rating
+	// Query to get average rating of the profile
 	avgRatingQuery := `SELECT COALESCE(AVG(pr.rating),  0) as avg_rating
                       FROM profile_reviews pr
                       JOIN profiles p ON pr.profile_id = p.id
-                      WHERE pr.has_deleted=false`
+                      WHERE pr.has_deleted=false AND pr.human_id=$1 AND pr.status='published'`
 	var avgRating float32
-	err = r.db.QueryRowContext(ctx, avgRatingQuery).Scan(&avgRating)
+	err = r.db.QueryRowContext(ctx, avgRatingQuery, humanID).Scan(&avgRating)
 	if err != nil {
-		r.logger.Debug("error func SelectReviewList, method QueryRowContext for avgRating by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectReviewList, method QueryRowContext for avgRating", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	// Rounding the average rating to the nearest multiple of 0.5
 	roundedAvgRating := float32(math.Round(float64(avgRating)*2)) / 2
 	size := qp.Size
 	page := qp.Page
 	// get totalItems
-	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery)
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, humanID)
 	if err != nil {
-		r.logger.Debug("error func SelectReviewList, method GetTotalItems by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectReviewList, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	// pagination
 	query = pagination.ApplyPagination(query, page, size)
 	countQuery = pagination.ApplyPagination(countQuery, page, size)
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, humanID)
 	if err != nil {
-		r.logger.Debug("error func SelectReviewList, method QueryContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectReviewList, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer rows.Close()
 	list := make([]*profile.ContentReviewProfile, 0)
 	for rows.Next() {
 		p := profile.ContentReviewProfile{}
-		err := rows.Scan(&p.ID, &p.ProfileID, &p.Message, &p.Rating, &p.HasDeleted, &p.HasEdited, &p.CreatedAt,
-			&p.UpdatedAt, &p.DisplayName, &p.SessionID)
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted, &p.HasEdited,
+			&p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DisplayName, &p.SessionID)
 		if err != nil {
-			r.logger.Debug("error func SelectReviewList, method Scan by path"+
-				" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+			r.logger.With(ctx).Error("error func SelectReviewList, method Scan", zap.Error(err))
 			continue
 		}
 		list = append(list, &p)
 	}
+	pr, isExist, err := r.FindReviewByHumanID(ctx, profileID, humanID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectReviewList, method FindReviewByHumanID", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
 	paging := pagination.GetPagination(size, page, totalItems)
 	response := profile.ResponseListReview{
 		Pagination:               paging,
@@ -903,29 +1164,141 @@ func (r *RepositoryProfile) SelectReviewList(
 		CountItemsTodayByProfile: count,
 		Content:                  list,
 	}
+	if isExist {
+		response.ProfileReviewID = &pr.ID
+	}
+	return &response, nil
+}
+
+// FindReviewByHumanID returns the review of the profile humanID written by the profile profileID
+func (r *RepositoryProfile) FindReviewByHumanID(
+	ctx context.Context, profileID uint64, humanID uint64) (*profile.ReviewProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindReviewByHumanID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindReviewByHumanID")
+	defer span.End()
+	p := profile.ReviewProfile{}
+	query := `SELECT id, profile_id, human_id, message, rating, has_deleted, has_edited, status, created_at,
+       updated_at
+			  FROM profile_reviews
+			  WHERE profile_id = $1 AND human_id = $2 AND has_deleted=false`
+	row := r.db.QueryRowContext(ctx, query, profileID, humanID)
+	err := row.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted, &p.HasEdited,
+		&p.Status, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindReviewByHumanID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// SelectListReviewByStatus returns the reviews with the moderation status, the oldest first
+func (r *RepositoryProfile) SelectListReviewByStatus(
+	ctx context.Context, qp *profile.QueryParamsReviewModerationList) (*profile.ResponseListReviewModeration, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListReviewByStatus", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListReviewByStatus")
+	defer span.End()
+	query := `SELECT pr.id, pr.profile_id, COALESCE(pr.human_id, 0), pr.message, pr.rating, pr.has_deleted,
+                pr.has_edited, pr.status, pr.created_at, pr.updated_at, p.display_name, p.session_id
+              FROM profile_reviews pr
+              JOIN profiles p ON pr.profile_id = p.id
+              WHERE pr.has_deleted=false AND pr.status=$1
+              ORDER BY pr.updated_at ASC`
+	countQuery := `SELECT COUNT(*) FROM profile_reviews pr
+                     WHERE pr.has_deleted=false AND pr.status=$1`
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListReviewByStatus, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	query = pagination.ApplyPagination(query, qp.Page, qp.Size)
+	rows, err := r.db.QueryContext(ctx, query, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListReviewByStatus, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentReviewProfile, 0)
+	for rows.Next() {
+		p := profile.ContentReviewProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted, &p.HasEdited,
+			&p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DisplayName, &p.SessionID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListReviewByStatus, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	paging := pagination.GetPagination(qp.Size, qp.Page, totalItems)
+	response := profile.ResponseListReviewModeration{
+		Pagination: paging,
+		Content:    list,
+	}
 	return &response, nil
 }
 
+// AddReviewReport records the report, a repeated report of the same profile is ignored. The published review
+// is held for moderation once it has been reported profile.ReviewReportLimit times.
+func (r *RepositoryProfile) AddReviewReport(
+	ctx context.Context, p *profile.ReviewReportProfile) (*profile.ReviewReportProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddReviewReport", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddReviewReport")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddReviewReport, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	query := `INSERT INTO profile_review_reports (review_id, profile_id, reason, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (review_id, profile_id)
+			  DO UPDATE SET reason=EXCLUDED.reason, updated_at=EXCLUDED.updated_at
+			  RETURNING id`
+	err = tx.QueryRowContext(ctx, query, &p.ReviewID, &p.ProfileID, &p.Reason, &p.CreatedAt,
+		&p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddReviewReport, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	holdQuery := `UPDATE profile_reviews SET status='pending', updated_at=$2
+			  WHERE id=$1 AND status='published'
+			    AND (SELECT COUNT(*) FROM profile_review_reports WHERE review_id=$1) >= $3`
+	_, err = tx.ExecContext(ctx, holdQuery, p.ReviewID, p.UpdatedAt, profile.ReviewReportLimit)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddReviewReport, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	tx.Commit()
+	return p, nil
+}
+
 func (r *RepositoryProfile) AddLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddLike", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddLike")
+	defer span.End()
 	query := `INSERT INTO profile_likes (profile_id, human_id, is_liked, created_at, updated_at)
 			  VALUES ($1, $2, $3, $4, $5)
 			  RETURNING id`
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt,
 		&p.UpdatedAt).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func AddLike, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddLike, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateLike", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateLike")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateLike, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateLike, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := `UPDATE profile_likes
@@ -933,37 +1306,58 @@ func (r *RepositoryProfile) UpdateLike(ctx context.Context, p *profile.LikeProfi
 			  WHERE id=$6`
 	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt, &p.UpdatedAt, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func UpdateLike, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateLike, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) DeleteLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteLike", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteLike")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func DeleteLike, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteLike, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := `UPDATE profile_likes
 			  SET profile_id=$1, human_id=$2, is_liked=$3, created_at=$4, updated_at=$5
 			  WHERE id=$6`
-	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt, &p.UpdatedAt, &p.ID)
+	_, err = tx.ExecContext(ctx, query, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt, &p.UpdatedAt, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func DeleteLike, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func DeleteLike, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	if err := r.expireLikeSwipes(ctx, tx, p); err != nil {
+		r.logger.With(ctx).Error("error func DeleteLike, method expireLikeSwipes", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.With(ctx).Error("error func DeleteLike, method Commit", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
-	tx.Commit()
 	return p, nil
 }
 
+// expireLikeSwipes expires the like and the superlike swipes of the removed like, so the profile returns to the feed
+func (r *RepositoryProfile) expireLikeSwipes(ctx context.Context, tx *sql.Tx, p *profile.LikeProfile) error {
+	query := `UPDATE profile_swipes
+			  SET expires_at=$3, updated_at=$3
+			  WHERE profile_id=$1 AND human_id=$2 AND action IN ($4, $5) AND is_rewound=false
+			  AND (expires_at IS NULL OR expires_at > $3)`
+	_, err := tx.ExecContext(ctx, query, p.ProfileID, p.HumanID, p.UpdatedAt, profile.SwipeActionLike,
+		profile.SwipeActionSuperLike)
+	return err
+}
+
 func (r *RepositoryProfile) FindLikeByHumanID(
 	ctx context.Context, profileID uint64, humanID uint64) (*profile.LikeProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindLikeByHumanID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindLikeByHumanID")
+	defer span.End()
 	p := profile.LikeProfile{}
 	query := `SELECT id, profile_id, human_id, is_liked, created_at, updated_at
 			  FROM profile_likes
@@ -974,14 +1368,16 @@ func (r *RepositoryProfile) FindLikeByHumanID(
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
-		r.logger.Debug("error func FindLikeByHumanID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, false, err
+		r.logger.With(ctx).Error("error func FindLikeByHumanID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
 	}
 	return &p, true, nil
 }
 
 func (r *RepositoryProfile) FindLikeByID(ctx context.Context, id uint64) (*profile.LikeProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindLikeByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindLikeByID")
+	defer span.End()
 	p := profile.LikeProfile{}
 	query := `SELECT id, profile_id, human_id, is_liked, created_at, updated_at
 			  FROM profile_likes
@@ -992,36 +1388,539 @@ func (r *RepositoryProfile) FindLikeByID(ctx context.Context, id uint64) (*profi
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
-		r.logger.Debug("error func FindLikeByID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, false, err
+		r.logger.With(ctx).Error("error func FindLikeByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
 	}
 	return &p, true, nil
 }
 
-func (r *RepositoryProfile) AddBlock(
-	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
-	query := `INSERT INTO profile_blocks (profile_id, blocked_user_id, is_blocked, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5)
-			  RETURNING id`
-	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.BlockedUserID, &p.IsBlocked, &p.CreatedAt,
-		&p.UpdatedAt).Scan(&p.ID)
+func (r *RepositoryProfile) SelectListLike(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsLikeList) (*profile.ResponseListLike, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListLike", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListLike")
+	defer span.End()
+	var directionCondition string
+	switch qp.Direction {
+	case profile.LikeDirectionIncoming:
+		directionCondition = "pl.human_id = $1 AND pl.is_liked=true"
+	case profile.LikeDirectionOutgoing:
+		directionCondition = "pl.profile_id = $1 AND pl.is_liked=true"
+	default:
+		directionCondition = "(pl.profile_id = $1 OR pl.human_id = $1)"
+	}
+	// The other side of the like must not be deleted, blocked or blocked with the profile in any direction
+	fromQuery := " FROM profile_likes pl" +
+		" JOIN profiles p ON p.id = CASE WHEN pl.profile_id = $1 THEN pl.human_id ELSE pl.profile_id END" +
+		" WHERE " + directionCondition + " AND p.is_deleted=false AND p.is_blocked=false AND" +
+		" NOT EXISTS (SELECT 1 FROM profile_blocks pb WHERE pb.is_blocked=true AND" +
+		" ((pb.profile_id = $1 AND pb.blocked_user_id = p.id) OR (pb.profile_id = p.id AND pb.blocked_user_id = $1)))"
+	query := "SELECT pl.id, pl.profile_id, pl.is_liked, pl.created_at, pl.updated_at, p.id, p.display_name" +
+		fromQuery + " ORDER BY pl.updated_at DESC, pl.id DESC"
+	countQuery := "SELECT COUNT(*)" + fromQuery
+	size := qp.Size
+	page := qp.Page
+	// get totalItems
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, profileID)
 	if err != nil {
-		r.logger.Debug("error func AddBlock, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectListLike, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
-	return p, nil
-}
-
-func (r *RepositoryProfile) UpdateBlock(
-	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
-	tx, err := r.db.Begin()
+	// pagination
+	query = pagination.ApplyPagination(query, page, size)
+	rows, err := r.db.QueryContext(ctx, query, profileID)
 	if err != nil {
-		r.logger.Debug("error func UpdateBlock, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+		r.logger.With(ctx).Error("error func SelectListLike, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentLikeProfile, 0)
+	for rows.Next() {
+		var likeProfileID uint64
+		l := profile.ContentLikeProfile{}
+		h := profile.ResponseLikeHumanProfile{}
+		err := rows.Scan(&l.ID, &likeProfileID, &l.IsLiked, &l.CreatedAt, &l.UpdatedAt, &h.ID, &h.DisplayName)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListLike, method Scan", zap.Error(err))
+			continue
+		}
+		l.Direction = profile.LikeDirectionOutgoing
+		if likeProfileID != profileID {
+			l.Direction = profile.LikeDirectionIncoming
+		}
+		l.Human = &h
+		list = append(list, &l)
+	}
+	for _, l := range list {
+		images, err := r.SelectListPublicImage(ctx, l.Human.ID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListLike, method SelectListPublicImage", zap.Error(err))
+			continue
+		}
+		if len(images) > 0 {
+			l.Human.Image = &profile.ResponseImageProfile{
+				Url: images[0].Url,
+			}
+		}
+	}
+	paging := pagination.GetPagination(size, page, totalItems)
+	response := profile.ResponseListLike{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
+}
+
+func (r *RepositoryProfile) FindLikeStatsByProfileID(
+	ctx context.Context, profileID uint64) (*profile.LikeStatsProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindLikeStatsByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindLikeStatsByProfileID")
+	defer span.End()
+	p := profile.LikeStatsProfile{ProfileID: profileID}
+	var ageAverage sql.NullFloat64
+	query := `SELECT COUNT(*), AVG(DATE_PART('year', AGE(p.birthday)))
+			  FROM profile_likes pl
+			  JOIN profiles p ON pl.human_id = p.id
+			  WHERE pl.profile_id = $1 AND pl.is_liked=true`
+	err := r.db.QueryRowContext(ctx, query, profileID).Scan(&p.CountLiked, &ageAverage)
+	if err != nil {
+		r.logger.With(ctx).Error("error func FindLikeStatsByProfileID, method Scan", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	p.AgeAverage = ageAverage.Float64
+	return &p, nil
+}
+
+func (r *RepositoryProfile) AddSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddSwipe", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddSwipe")
+	defer span.End()
+	query := `INSERT INTO profile_swipes (profile_id, human_id, action, is_rewound, expires_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.HumanID, &p.Action, &p.IsRewound, p.ExpiresAt,
+		&p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddSwipe, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) UpdateSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateSwipe", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateSwipe")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateSwipe, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	query := `UPDATE profile_swipes
+			  SET profile_id=$1, human_id=$2, action=$3, is_rewound=$4, expires_at=$5, created_at=$6, updated_at=$7
+			  WHERE id=$8`
+	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.HumanID, &p.Action, &p.IsRewound, p.ExpiresAt,
+		&p.CreatedAt, &p.UpdatedAt, &p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateSwipe, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	tx.Commit()
+	return p, nil
+}
+
+func (r *RepositoryProfile) FindLastPassSwipe(
+	ctx context.Context, profileID uint64) (*profile.SwipeProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindLastPassSwipe", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindLastPassSwipe")
+	defer span.End()
+	p := profile.SwipeProfile{}
+	query := `SELECT id, profile_id, human_id, action, is_rewound, expires_at, created_at, updated_at
+			  FROM profile_swipes
+			  WHERE profile_id=$1 AND action=$2 AND is_rewound=false
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, profileID, profile.SwipeActionPass).
+		Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Action, &p.IsRewound, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindLastPassSwipe, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+func (r *RepositoryProfile) CountRewindTodayByProfileID(ctx context.Context, profileID uint64) (uint64, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "CountRewindTodayByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.CountRewindTodayByProfileID")
+	defer span.End()
+	var count uint64
+	query := `SELECT COUNT(*)
+			  FROM profile_swipes
+			  WHERE profile_id=$1 AND is_rewound=true AND updated_at::date = (NOW() AT TIME ZONE 'UTC')::date`
+	err := r.db.QueryRowContext(ctx, query, profileID).Scan(&count)
+	if err != nil {
+		r.logger.With(ctx).Error("error func CountRewindTodayByProfileID, method Scan", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	return count, nil
+}
+
+// ReserveActionTodayByProfileID counts one more action of the profile today unless the limit has been reached. The
+// check and the count are one statement, so the concurrent requests can't go beyond the limit.
+func (r *RepositoryProfile) ReserveActionTodayByProfileID(
+	ctx context.Context, action string, profileID uint64, limit uint64) (uint64, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "ReserveActionTodayByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.ReserveActionTodayByProfileID")
+	defer span.End()
+	if limit == 0 {
+		return 0, false, nil
+	}
+	var used uint64
+	query := `INSERT INTO profile_quota_usages (profile_id, action, day, used)
+			  VALUES ($1, $2, (NOW() AT TIME ZONE 'UTC')::date, 1)
+			  ON CONFLICT (profile_id, action, day) DO UPDATE SET used = profile_quota_usages.used + 1
+			  WHERE profile_quota_usages.used < $3
+			  RETURNING used`
+	err := r.db.QueryRowContext(ctx, query, profileID, action, limit).Scan(&used)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return limit, false, nil
+		}
+		r.logger.With(ctx).Error("error func ReserveActionTodayByProfileID, method Scan", zap.Error(err))
+		return 0, false, psqlRepo.MapError(err)
+	}
+	return used, true, nil
+}
+
+// ReleaseActionTodayByProfileID gives back the action which has been reserved today but not performed
+func (r *RepositoryProfile) ReleaseActionTodayByProfileID(ctx context.Context, action string, profileID uint64) error {
+	defer r.metrics.ObserveQuery(repositoryName, "ReleaseActionTodayByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.ReleaseActionTodayByProfileID")
+	defer span.End()
+	query := `UPDATE profile_quota_usages SET used = used - 1
+			  WHERE profile_id=$1 AND action=$2 AND day = (NOW() AT TIME ZONE 'UTC')::date AND used > 0`
+	_, err := r.db.ExecContext(ctx, query, profileID, action)
+	if err != nil {
+		r.logger.With(ctx).Error("error func ReleaseActionTodayByProfileID, method ExecContext", zap.Error(err))
+		return psqlRepo.MapError(err)
+	}
+	return nil
+}
+
+func (r *RepositoryProfile) AddSubscription(
+	ctx context.Context, p *profile.SubscriptionProfile) (*profile.SubscriptionProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddSubscription", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddSubscription")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddSubscription, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	if err := r.addSubscription(ctx, tx, p); err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		r.logger.With(ctx).Error("error func AddSubscription, method Commit", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+// addSubscription adds the subscription and turns the premium features of the profile on in the transaction
+func (r *RepositoryProfile) addSubscription(ctx context.Context, tx *sql.Tx, p *profile.SubscriptionProfile) error {
+	query := `INSERT INTO profile_subscriptions
+			  (profile_id, plan, period, status, started_at, ended_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING id`
+	err := tx.QueryRowContext(ctx, query, &p.ProfileID, &p.Plan, &p.Period, &p.Status, &p.StartedAt, &p.EndedAt,
+		&p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func addSubscription, method QueryRowContext", zap.Error(err))
+		return psqlRepo.MapError(err)
+	}
+	query = `UPDATE profiles SET is_premium=true WHERE id=$1`
+	_, err = tx.ExecContext(ctx, query, &p.ProfileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func addSubscription, method ExecContext", zap.Error(err))
+		return psqlRepo.MapError(err)
+	}
+	return nil
+}
+
+func (r *RepositoryProfile) FindActiveSubscriptionByProfileID(
+	ctx context.Context, profileID uint64) (*profile.SubscriptionProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindActiveSubscriptionByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindActiveSubscriptionByProfileID")
+	defer span.End()
+	p := profile.SubscriptionProfile{}
+	query := `SELECT id, profile_id, plan, period, status, started_at, ended_at, created_at, updated_at
+			  FROM profile_subscriptions
+			  WHERE profile_id = $1 AND status = $2 AND ended_at > NOW() AT TIME ZONE 'UTC'
+			  ORDER BY ended_at DESC
+			  LIMIT 1`
+	row := r.db.QueryRowContext(ctx, query, profileID, profile.SubscriptionStatusActive)
+	err := row.Scan(&p.ID, &p.ProfileID, &p.Plan, &p.Period, &p.Status, &p.StartedAt, &p.EndedAt,
+		&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindActiveSubscriptionByProfileID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// ExpireSubscriptions marks the ended subscriptions as expired and takes the premium features away
+// from the profiles which have no active subscription left. It returns the number of such profiles.
+func (r *RepositoryProfile) ExpireSubscriptions(ctx context.Context) (int64, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "ExpireSubscriptions", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.ExpireSubscriptions")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func ExpireSubscriptions, method Begin", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	query := `UPDATE profile_subscriptions
+			  SET status = $1, updated_at = NOW() AT TIME ZONE 'UTC'
+			  WHERE status = $2 AND ended_at <= NOW() AT TIME ZONE 'UTC'`
+	_, err = tx.ExecContext(ctx, query, profile.SubscriptionStatusExpired, profile.SubscriptionStatusActive)
+	if err != nil {
+		r.logger.With(ctx).Error("error func ExpireSubscriptions, method ExecContext", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	query = `UPDATE profiles
+			  SET is_premium = false, is_invisible = false
+			  WHERE is_premium = true AND NOT EXISTS (
+				  SELECT 1 FROM profile_subscriptions ps WHERE ps.profile_id = profiles.id AND ps.status = $1)`
+	result, err := tx.ExecContext(ctx, query, profile.SubscriptionStatusActive)
+	if err != nil {
+		r.logger.With(ctx).Error("error func ExpireSubscriptions, method ExecContext", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		r.logger.With(ctx).Error("error func ExpireSubscriptions, method RowsAffected", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	tx.Commit()
+	return count, nil
+}
+
+func (r *RepositoryProfile) AddPayment(ctx context.Context, p *profile.PaymentProfile) (*profile.PaymentProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddPayment", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddPayment")
+	defer span.End()
+	query := `INSERT INTO profile_payments (profile_id, plan, period, amount, currency, status, payload,
+			  telegram_payment_charge_id, provider_payment_charge_id, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Plan, &p.Period, &p.Amount, &p.Currency, &p.Status,
+		&p.Payload, &p.TelegramPaymentChargeID, &p.ProviderPaymentChargeID, &p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddPayment, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+// CompletePayment marks the pending payment as paid and adds the subscription which it pays for in one
+// transaction. False is returned when the payment is not pending any more, nothing is changed then.
+func (r *RepositoryProfile) CompletePayment(ctx context.Context, p *profile.PaymentProfile,
+	s *profile.SubscriptionProfile) (*profile.SubscriptionProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "CompletePayment", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.CompletePayment")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func CompletePayment, method Begin", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	query := `UPDATE profile_payments
+			  SET status=$1, telegram_payment_charge_id=$2, provider_payment_charge_id=$3, updated_at=$4
+			  WHERE id=$5 AND status=$6`
+	result, err := tx.ExecContext(ctx, query, &p.Status, &p.TelegramPaymentChargeID, &p.ProviderPaymentChargeID,
+		&p.UpdatedAt, &p.ID, profile.PaymentStatusPending)
+	if err != nil {
+		r.logger.With(ctx).Error("error func CompletePayment, method ExecContext", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		r.logger.With(ctx).Error("error func CompletePayment, method RowsAffected", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	if count == 0 {
+		return nil, false, nil
+	}
+	if err := r.addSubscription(ctx, tx, s); err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.With(ctx).Error("error func CompletePayment, method Commit", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return s, true, nil
+}
+
+func (r *RepositoryProfile) FindPaymentByPayload(
+	ctx context.Context, payload string) (*profile.PaymentProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindPaymentByPayload", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindPaymentByPayload")
+	defer span.End()
+	p := profile.PaymentProfile{}
+	query := `SELECT id, profile_id, plan, period, amount, currency, status, payload,
+			  telegram_payment_charge_id, provider_payment_charge_id, created_at, updated_at
+			  FROM profile_payments
+			  WHERE payload = $1`
+	row := r.db.QueryRowContext(ctx, query, payload)
+	err := row.Scan(&p.ID, &p.ProfileID, &p.Plan, &p.Period, &p.Amount, &p.Currency, &p.Status, &p.Payload,
+		&p.TelegramPaymentChargeID, &p.ProviderPaymentChargeID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindPaymentByPayload, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// AddVisit records the visit, the repeated visits of the same day are merged into one
+func (r *RepositoryProfile) AddVisit(ctx context.Context, p *profile.VisitProfile) (*profile.VisitProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddVisit", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddVisit")
+	defer span.End()
+	query := `INSERT INTO profile_visits (profile_id, viewer_id, visited_on, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (profile_id, viewer_id, visited_on) DO UPDATE SET updated_at = EXCLUDED.updated_at
+			  RETURNING id, created_at`
+	// The visit is counted once a day, the day is the UTC date of the visit
+	visitedOn := p.CreatedAt.UTC().Format(time.DateOnly)
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.ViewerID, visitedOn, &p.CreatedAt, &p.UpdatedAt).Scan(
+		&p.ID, &p.CreatedAt)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddVisit, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) SelectListVisit(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsVisitList) (*profile.ResponseListVisit, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListVisit", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListVisit")
+	defer span.End()
+	// The viewer must not be deleted, blocked, invisible or blocked with the profile in any direction
+	fromQuery := " FROM profile_visits pv" +
+		" JOIN profiles p ON p.id = pv.viewer_id" +
+		" WHERE pv.profile_id = $1 AND p.is_deleted=false AND p.is_blocked=false AND p.is_invisible=false AND" +
+		" NOT EXISTS (SELECT 1 FROM profile_blocks pb WHERE pb.is_blocked=true AND" +
+		" ((pb.profile_id = $1 AND pb.blocked_user_id = p.id) OR (pb.profile_id = p.id AND pb.blocked_user_id = $1)))"
+	query := "SELECT pv.id, pv.updated_at, p.id, p.display_name" + fromQuery + " ORDER BY pv.updated_at DESC, pv.id DESC"
+	countQuery := "SELECT COUNT(*)" + fromQuery
+	size := qp.Size
+	page := qp.Page
+	// get totalItems
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListVisit, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	// pagination
+	query = pagination.ApplyPagination(query, page, size)
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListVisit, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentVisitProfile, 0)
+	for rows.Next() {
+		v := profile.ContentVisitProfile{}
+		viewer := profile.ResponseVisitViewerProfile{}
+		err := rows.Scan(&v.ID, &v.VisitedAt, &viewer.ID, &viewer.DisplayName)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListVisit, method Scan", zap.Error(err))
+			continue
+		}
+		v.Viewer = &viewer
+		list = append(list, &v)
+	}
+	for _, v := range list {
+		images, err := r.SelectListPublicImage(ctx, v.Viewer.ID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListVisit, method SelectListPublicImage", zap.Error(err))
+			continue
+		}
+		if len(images) > 0 {
+			v.Viewer.Image = &profile.ResponseImageProfile{
+				Url: images[0].Url,
+			}
+		}
+	}
+	paging := pagination.GetPagination(size, page, totalItems)
+	response := profile.ResponseListVisit{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
+}
+
+// DeleteVisitBefore deletes the visits which were last updated before the moment and returns their number
+func (r *RepositoryProfile) DeleteVisitBefore(ctx context.Context, moment time.Time) (int64, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "DeleteVisitBefore", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.DeleteVisitBefore")
+	defer span.End()
+	query := `DELETE FROM profile_visits WHERE updated_at < $1`
+	result, err := r.db.ExecContext(ctx, query, moment)
+	if err != nil {
+		r.logger.With(ctx).Error("error func DeleteVisitBefore, method ExecContext", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		r.logger.With(ctx).Error("error func DeleteVisitBefore, method RowsAffected", zap.Error(err))
+		return 0, psqlRepo.MapError(err)
+	}
+	return count, nil
+}
+
+func (r *RepositoryProfile) AddBlock(
+	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddBlock", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddBlock")
+	defer span.End()
+	query := `INSERT INTO profile_blocks (profile_id, blocked_user_id, is_blocked, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.BlockedUserID, &p.IsBlocked, &p.CreatedAt,
+		&p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddBlock, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) UpdateBlock(
+	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateBlock", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateBlock")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateBlock, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
 	defer tx.Rollback()
 	query := `UPDATE profile_blocks
 			  SET profile_id=$1, blocked_user_id=$2, is_blocked=$3, created_at=$4, updated_at=$5
@@ -1029,15 +1928,17 @@ func (r *RepositoryProfile) UpdateBlock(
 	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.BlockedUserID, &p.IsBlocked, &p.CreatedAt, &p.UpdatedAt,
 		&p.ID)
 	if err != nil {
-		r.logger.Debug("error func UpdateBlock, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateBlock, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) FindBlockByID(ctx context.Context, id uint64) (*profile.BlockedProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindBlockByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindBlockByID")
+	defer span.End()
 	p := profile.BlockedProfile{}
 	query := `SELECT id, profile_id, blocked_user_id, is_blocked, created_at, updated_at
 			  FROM profile_blocks
@@ -1048,35 +1949,137 @@ func (r *RepositoryProfile) FindBlockByID(ctx context.Context, id uint64) (*prof
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
-		r.logger.Debug("error func FindBlockByID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, false, err
+		r.logger.With(ctx).Error("error func FindBlockByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// FindBlockByHumanID returns the last block of the user by the profile, the block may have been lifted
+func (r *RepositoryProfile) FindBlockByHumanID(
+	ctx context.Context, profileID, blockedUserID uint64) (*profile.BlockedProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindBlockByHumanID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindBlockByHumanID")
+	defer span.End()
+	p := profile.BlockedProfile{}
+	query := `SELECT id, profile_id, blocked_user_id, is_blocked, created_at, updated_at
+			  FROM profile_blocks
+			  WHERE profile_id=$1 AND blocked_user_id=$2
+			  ORDER BY id DESC
+			  LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, profileID, blockedUserID).
+		Scan(&p.ID, &p.ProfileID, &p.BlockedUserID, &p.IsBlocked, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindBlockByHumanID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
 	}
 	return &p, true, nil
 }
 
+// CheckIfBlockExists reports whether either of the profiles has blocked the other one
+func (r *RepositoryProfile) CheckIfBlockExists(ctx context.Context, profileID, humanID uint64) (bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "CheckIfBlockExists", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.CheckIfBlockExists")
+	defer span.End()
+	var isExist bool
+	query := "SELECT EXISTS (SELECT 1 FROM profile_blocks WHERE is_blocked=true AND" +
+		" ((profile_id = $1 AND blocked_user_id = $2) OR (profile_id = $2 AND blocked_user_id = $1)))"
+	err := r.db.QueryRowContext(ctx, query, profileID, humanID).Scan(&isExist)
+	if err != nil {
+		r.logger.With(ctx).Error("error func CheckIfBlockExists, method Scan", zap.Error(err))
+		return false, psqlRepo.MapError(err)
+	}
+	return isExist, nil
+}
+
+func (r *RepositoryProfile) SelectListBlock(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsBlockList) (*profile.ResponseListBlock, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListBlock", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListBlock")
+	defer span.End()
+	fromQuery := " FROM profile_blocks pb" +
+		" JOIN profiles p ON p.id = pb.blocked_user_id" +
+		" WHERE pb.profile_id = $1 AND pb.is_blocked=true AND p.is_deleted=false"
+	query := "SELECT pb.id, pb.updated_at, p.id, p.display_name" + fromQuery +
+		" ORDER BY pb.updated_at DESC, pb.id DESC"
+	countQuery := "SELECT COUNT(*)" + fromQuery
+	size := qp.Size
+	page := qp.Page
+	// get totalItems
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListBlock, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	// pagination
+	query = pagination.ApplyPagination(query, page, size)
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListBlock, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentBlockProfile, 0)
+	for rows.Next() {
+		b := profile.ContentBlockProfile{}
+		blockedUser := profile.ResponseBlockedUserProfile{}
+		err := rows.Scan(&b.ID, &b.BlockedAt, &blockedUser.ID, &blockedUser.DisplayName)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListBlock, method Scan", zap.Error(err))
+			continue
+		}
+		b.BlockedUser = &blockedUser
+		list = append(list, &b)
+	}
+	for _, b := range list {
+		images, err := r.SelectListPublicImage(ctx, b.BlockedUser.ID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListBlock, method SelectListPublicImage", zap.Error(err))
+			continue
+		}
+		if len(images) > 0 {
+			b.BlockedUser.Image = &profile.ResponseImageProfile{
+				Url: images[0].Url,
+			}
+		}
+	}
+	paging := pagination.GetPagination(size, page, totalItems)
+	response := profile.ResponseListBlock{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
+}
+
 func (r *RepositoryProfile) AddComplaint(
 	ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddComplaint", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddComplaint")
+	defer span.End()
 	query := `INSERT INTO profile_complaints (profile_id, complaint_user_id, reason, created_at, updated_at)
 			  VALUES ($1, $2, $3, $4, $5)
 			  RETURNING id`
 	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.ComplaintUserID, &p.Reason,
 		&p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
 	if err != nil {
-		r.logger.Debug("error func AddComplaint, method QueryRowContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func AddComplaint, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	return p, nil
 }
 
 func (r *RepositoryProfile) UpdateComplaint(
 	ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateComplaint", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateComplaint")
+	defer span.End()
 	tx, err := r.db.Begin()
 	if err != nil {
-		r.logger.Debug("error func UpdateComplaint, method Begin by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateComplaint, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer tx.Rollback()
 	query := `UPDATE profile_complaints
@@ -1085,15 +2088,17 @@ func (r *RepositoryProfile) UpdateComplaint(
 	_, err = r.db.ExecContext(ctx, query, &p.ProfileID, &p.ComplaintUserID, &p.Reason, &p.CreatedAt,
 		&p.UpdatedAt, &p.ID)
 	if err != nil {
-		r.logger.Debug("error func UpdateComplaint, method ExecContext by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func UpdateComplaint, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	tx.Commit()
 	return p, nil
 }
 
 func (r *RepositoryProfile) FindComplaintByID(ctx context.Context, id uint64) (*profile.ComplaintProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindComplaintByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindComplaintByID")
+	defer span.End()
 	p := profile.ComplaintProfile{}
 	query := `SELECT id, profile_id, complaint_user_id, reason, created_at, updated_at
 			  FROM profile_complaints
@@ -1104,23 +2109,141 @@ func (r *RepositoryProfile) FindComplaintByID(ctx context.Context, id uint64) (*
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
-		r.logger.Debug("error func FindComplaintByID, method Scan by path"+
-			" internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, false, err
+		r.logger.With(ctx).Error("error func FindComplaintByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
 	}
 	return &p, true, nil
 }
 
 func (r *RepositoryProfile) SelectListComplaintByID(
 	ctx context.Context, complaintUserID uint64) ([]*profile.ComplaintProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListComplaintByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListComplaintByID")
+	defer span.End()
 	query := `SELECT id, profile_id, complaint_user_id, reason, created_at, updated_at
 	FROM profile_complaints
 	WHERE complaint_user_id=$1`
 	rows, err := r.db.QueryContext(ctx, query, complaintUserID)
 	if err != nil {
-		r.logger.Debug("error func SelectListComplaintByID,"+
-			" method QueryContext by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
-		return nil, err
+		r.logger.With(ctx).Error("error func SelectListComplaintByID,"+
+			" method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ComplaintProfile, 0)
+	for rows.Next() {
+		p := profile.ComplaintProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.ComplaintUserID, &p.Reason, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListComplaintByID,"+
+				" method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// SelectListLikeByProfileID returns the likes which the profile has sent
+func (r *RepositoryProfile) SelectListLikeByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.LikeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListLikeByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListLikeByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, human_id, is_liked, created_at, updated_at
+			  FROM profile_likes
+			  WHERE profile_id=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListLikeByProfileID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.LikeProfile, 0)
+	for rows.Next() {
+		p := profile.LikeProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListLikeByProfileID, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// SelectListLikeByHumanID returns the likes which the profile has received
+func (r *RepositoryProfile) SelectListLikeByHumanID(
+	ctx context.Context, profileID uint64) ([]*profile.LikeProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListLikeByHumanID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListLikeByHumanID")
+	defer span.End()
+	query := `SELECT id, profile_id, human_id, is_liked, created_at, updated_at
+			  FROM profile_likes
+			  WHERE human_id=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListLikeByHumanID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.LikeProfile, 0)
+	for rows.Next() {
+		p := profile.LikeProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.IsLiked, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListLikeByHumanID, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+func (r *RepositoryProfile) SelectListBlockByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.BlockedProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListBlockByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListBlockByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, blocked_user_id, is_blocked, created_at, updated_at
+			  FROM profile_blocks
+			  WHERE profile_id=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListBlockByProfileID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.BlockedProfile, 0)
+	for rows.Next() {
+		p := profile.BlockedProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.BlockedUserID, &p.IsBlocked, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListBlockByProfileID, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// SelectListComplaintByProfileID returns the complaints which the profile has filed
+func (r *RepositoryProfile) SelectListComplaintByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.ComplaintProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListComplaintByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListComplaintByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, complaint_user_id, reason, created_at, updated_at
+			  FROM profile_complaints
+			  WHERE profile_id=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListComplaintByProfileID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
 	}
 	defer rows.Close()
 	list := make([]*profile.ComplaintProfile, 0)
@@ -1128,8 +2251,457 @@ func (r *RepositoryProfile) SelectListComplaintByID(
 		p := profile.ComplaintProfile{}
 		err := rows.Scan(&p.ID, &p.ProfileID, &p.ComplaintUserID, &p.Reason, &p.CreatedAt, &p.UpdatedAt)
 		if err != nil {
-			r.logger.Debug("error func SelectListComplaintByID,"+
-				" method Scan by path internal/adapter/psqlRepo/profile/profile.go", zap.Error(err))
+			r.logger.With(ctx).Error("error func SelectListComplaintByProfileID, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// SelectListReviewByProfileID returns the reviews which the profile has written
+func (r *RepositoryProfile) SelectListReviewByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.ReviewProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListReviewByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListReviewByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, COALESCE(human_id, 0), message, rating, has_deleted, has_edited, status,
+       created_at, updated_at
+			  FROM profile_reviews
+			  WHERE profile_id=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListReviewByProfileID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ReviewProfile, 0)
+	for rows.Next() {
+		p := profile.ReviewProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.HumanID, &p.Message, &p.Rating, &p.HasDeleted, &p.HasEdited,
+			&p.Status, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListReviewByProfileID, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+func (r *RepositoryProfile) AddExport(ctx context.Context, p *profile.ExportProfile) (*profile.ExportProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddExport", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddExport")
+	defer span.End()
+	query := `INSERT INTO profile_exports (profile_id, status, token, file_path, expires_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Status, &p.Token, &p.FilePath, &p.ExpiresAt,
+		&p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddExport, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) UpdateExport(
+	ctx context.Context, p *profile.ExportProfile) (*profile.ExportProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateExport", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateExport")
+	defer span.End()
+	tx, err := r.db.Begin()
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateExport, method Begin", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer tx.Rollback()
+	query := `UPDATE profile_exports
+			  SET status=$1, file_path=$2, expires_at=$3, updated_at=$4
+			  WHERE id=$5`
+	_, err = r.db.ExecContext(ctx, query, &p.Status, &p.FilePath, &p.ExpiresAt, &p.UpdatedAt, &p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateExport, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	tx.Commit()
+	return p, nil
+}
+
+func (r *RepositoryProfile) FindExportByID(ctx context.Context, id uint64) (*profile.ExportProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindExportByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindExportByID")
+	defer span.End()
+	p := profile.ExportProfile{}
+	var filePath sql.NullString
+	query := `SELECT id, profile_id, status, token, file_path, expires_at, created_at, updated_at
+			  FROM profile_exports
+			  WHERE id=$1`
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&p.ID, &p.ProfileID, &p.Status, &p.Token, &filePath, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindExportByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	p.FilePath = filePath.String
+	return &p, true, nil
+}
+
+// FindPendingExportByProfileID returns the export of the profile which is being prepared
+func (r *RepositoryProfile) FindPendingExportByProfileID(
+	ctx context.Context, profileID uint64) (*profile.ExportProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindPendingExportByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindPendingExportByProfileID")
+	defer span.End()
+	p := profile.ExportProfile{}
+	var filePath sql.NullString
+	query := `SELECT id, profile_id, status, token, file_path, expires_at, created_at, updated_at
+			  FROM profile_exports
+			  WHERE profile_id=$1 AND status='pending'
+			  ORDER BY created_at DESC
+			  LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, profileID).
+		Scan(&p.ID, &p.ProfileID, &p.Status, &p.Token, &filePath, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindPendingExportByProfileID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	p.FilePath = filePath.String
+	return &p, true, nil
+}
+
+func (r *RepositoryProfile) SelectListExportByStatus(
+	ctx context.Context, status string) ([]*profile.ExportProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListExportByStatus", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListExportByStatus")
+	defer span.End()
+	query := `SELECT id, profile_id, status, token, file_path, expires_at, created_at, updated_at
+			  FROM profile_exports
+			  WHERE status=$1
+			  ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListExportByStatus, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ExportProfile, 0)
+	for rows.Next() {
+		p := profile.ExportProfile{}
+		var filePath sql.NullString
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Status, &p.Token, &filePath, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListExportByStatus, method Scan", zap.Error(err))
+			continue
+		}
+		p.FilePath = filePath.String
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+func (r *RepositoryProfile) SelectListExportByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.ExportProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListExportByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListExportByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, status, token, file_path, expires_at, created_at, updated_at
+			  FROM profile_exports
+			  WHERE profile_id=$1`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListExportByProfileID, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ExportProfile, 0)
+	for rows.Next() {
+		p := profile.ExportProfile{}
+		var filePath sql.NullString
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Status, &p.Token, &filePath, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListExportByProfileID, method Scan", zap.Error(err))
+			continue
+		}
+		p.FilePath = filePath.String
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+func (r *RepositoryProfile) AddAgeVerification(
+	ctx context.Context, p *profile.AgeVerificationProfile) (*profile.AgeVerificationProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddAgeVerification", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddAgeVerification")
+	defer span.End()
+	query := `INSERT INTO profile_age_verifications (profile_id, status, reason, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Status, &p.Reason, &p.CreatedAt, &p.UpdatedAt).
+		Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddAgeVerification, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) UpdateAgeVerification(
+	ctx context.Context, p *profile.AgeVerificationProfile) (*profile.AgeVerificationProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateAgeVerification", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateAgeVerification")
+	defer span.End()
+	query := `UPDATE profile_age_verifications
+			  SET status=$1, reason=$2, updated_at=$3
+			  WHERE id=$4`
+	_, err := r.db.ExecContext(ctx, query, &p.Status, &p.Reason, &p.UpdatedAt, &p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateAgeVerification, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) FindAgeVerificationByID(
+	ctx context.Context, id uint64) (*profile.AgeVerificationProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindAgeVerificationByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindAgeVerificationByID")
+	defer span.End()
+	p := profile.AgeVerificationProfile{}
+	query := `SELECT id, profile_id, status, reason, created_at, updated_at
+			  FROM profile_age_verifications
+			  WHERE id=$1`
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&p.ID, &p.ProfileID, &p.Status, &p.Reason, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindAgeVerificationByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// FindPendingAgeVerificationByProfileID returns the check of the age of the profile which waits for a moderator
+func (r *RepositoryProfile) FindPendingAgeVerificationByProfileID(
+	ctx context.Context, profileID uint64) (*profile.AgeVerificationProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindPendingAgeVerificationByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindPendingAgeVerificationByProfileID")
+	defer span.End()
+	p := profile.AgeVerificationProfile{}
+	query := `SELECT id, profile_id, status, reason, created_at, updated_at
+			  FROM profile_age_verifications
+			  WHERE profile_id=$1 AND status='pending'`
+	err := r.db.QueryRowContext(ctx, query, profileID).
+		Scan(&p.ID, &p.ProfileID, &p.Status, &p.Reason, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindPendingAgeVerificationByProfileID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+func (r *RepositoryProfile) SelectListAgeVerificationByStatus(
+	ctx context.Context, qp *profile.QueryParamsAgeVerificationList) (*profile.ResponseListAgeVerification, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListAgeVerificationByStatus", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListAgeVerificationByStatus")
+	defer span.End()
+	query := `SELECT pav.id, pav.profile_id, pav.status, pav.reason, pav.created_at, pav.updated_at,
+                p.display_name, p.birthday, p.session_id
+              FROM profile_age_verifications pav
+              JOIN profiles p ON pav.profile_id = p.id
+              WHERE pav.status=$1
+              ORDER BY pav.created_at ASC`
+	countQuery := `SELECT COUNT(*) FROM profile_age_verifications pav
+                     WHERE pav.status=$1`
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListAgeVerificationByStatus, method GetTotalItems", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	query = pagination.ApplyPagination(query, qp.Page, qp.Size)
+	rows, err := r.db.QueryContext(ctx, query, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListAgeVerificationByStatus, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentAgeVerificationProfile, 0)
+	for rows.Next() {
+		p := profile.ContentAgeVerificationProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Status, &p.Reason, &p.CreatedAt, &p.UpdatedAt, &p.DisplayName,
+			&p.Birthday, &p.SessionID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListAgeVerificationByStatus, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	paging := pagination.GetPagination(qp.Size, qp.Page, totalItems)
+	response := profile.ResponseListAgeVerification{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
+}
+
+func (r *RepositoryProfile) AddSelfieVerification(
+	ctx context.Context, p *profile.SelfieVerificationProfile) (*profile.SelfieVerificationProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "AddSelfieVerification", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.AddSelfieVerification")
+	defer span.End()
+	query := `INSERT INTO profile_selfie_verifications
+			  (profile_id, pose, status, file_path, score, expires_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, &p.ProfileID, &p.Pose, &p.Status, &p.FilePath, &p.Score, &p.ExpiresAt,
+		&p.CreatedAt, &p.UpdatedAt).Scan(&p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func AddSelfieVerification, method QueryRowContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) UpdateSelfieVerification(
+	ctx context.Context, p *profile.SelfieVerificationProfile) (*profile.SelfieVerificationProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "UpdateSelfieVerification", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.UpdateSelfieVerification")
+	defer span.End()
+	query := `UPDATE profile_selfie_verifications
+			  SET status=$1, file_path=$2, score=$3, updated_at=$4
+			  WHERE id=$5`
+	_, err := r.db.ExecContext(ctx, query, &p.Status, &p.FilePath, &p.Score, &p.UpdatedAt, &p.ID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func UpdateSelfieVerification, method ExecContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	return p, nil
+}
+
+func (r *RepositoryProfile) FindSelfieVerificationByID(
+	ctx context.Context, id uint64) (*profile.SelfieVerificationProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindSelfieVerificationByID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindSelfieVerificationByID")
+	defer span.End()
+	p := profile.SelfieVerificationProfile{}
+	query := `SELECT id, profile_id, pose, status, file_path, score, expires_at, created_at, updated_at
+			  FROM profile_selfie_verifications
+			  WHERE id=$1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.ProfileID, &p.Pose, &p.Status, &p.FilePath,
+		&p.Score, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindSelfieVerificationByID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+// FindLastSelfieVerificationByProfileID returns the latest check of the profile by the selfie
+func (r *RepositoryProfile) FindLastSelfieVerificationByProfileID(
+	ctx context.Context, profileID uint64) (*profile.SelfieVerificationProfile, bool, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "FindLastSelfieVerificationByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.FindLastSelfieVerificationByProfileID")
+	defer span.End()
+	p := profile.SelfieVerificationProfile{}
+	query := `SELECT id, profile_id, pose, status, file_path, score, expires_at, created_at, updated_at
+			  FROM profile_selfie_verifications
+			  WHERE profile_id=$1
+			  ORDER BY created_at DESC
+			  LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, profileID).Scan(&p.ID, &p.ProfileID, &p.Pose, &p.Status, &p.FilePath,
+		&p.Score, &p.ExpiresAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		r.logger.With(ctx).Error("error func FindLastSelfieVerificationByProfileID, method Scan", zap.Error(err))
+		return nil, false, psqlRepo.MapError(err)
+	}
+	return &p, true, nil
+}
+
+func (r *RepositoryProfile) SelectListSelfieVerificationByStatus(ctx context.Context,
+	qp *profile.QueryParamsSelfieModerationList) (*profile.ResponseListSelfieModeration, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListSelfieVerificationByStatus", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListSelfieVerificationByStatus")
+	defer span.End()
+	query := `SELECT psv.id, psv.profile_id, psv.pose, psv.status, psv.score, psv.created_at, psv.updated_at,
+                p.display_name, p.session_id
+              FROM profile_selfie_verifications psv
+              JOIN profiles p ON psv.profile_id = p.id
+              WHERE psv.status=$1
+              ORDER BY psv.updated_at ASC`
+	countQuery := `SELECT COUNT(*) FROM profile_selfie_verifications psv
+                     WHERE psv.status=$1`
+	totalItems, err := pagination.GetTotalItems(ctx, r.db, countQuery, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListSelfieVerificationByStatus, method GetTotalItems",
+			zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	query = pagination.ApplyPagination(query, qp.Page, qp.Size)
+	rows, err := r.db.QueryContext(ctx, query, qp.Status)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListSelfieVerificationByStatus, method QueryContext", zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.ContentSelfieVerificationProfile, 0)
+	for rows.Next() {
+		p := profile.ContentSelfieVerificationProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Pose, &p.Status, &p.Score, &p.CreatedAt, &p.UpdatedAt,
+			&p.DisplayName, &p.SessionID)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListSelfieVerificationByStatus, method Scan", zap.Error(err))
+			continue
+		}
+		list = append(list, &p)
+	}
+	paging := pagination.GetPagination(qp.Size, qp.Page, totalItems)
+	response := profile.ResponseListSelfieModeration{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
+}
+
+func (r *RepositoryProfile) SelectListSelfieVerificationByProfileID(
+	ctx context.Context, profileID uint64) ([]*profile.SelfieVerificationProfile, error) {
+	defer r.metrics.ObserveQuery(repositoryName, "SelectListSelfieVerificationByProfileID", time.Now())
+	ctx, span := tracing.Start(ctx, "RepositoryProfile.SelectListSelfieVerificationByProfileID")
+	defer span.End()
+	query := `SELECT id, profile_id, pose, status, file_path, score, expires_at, created_at, updated_at
+			  FROM profile_selfie_verifications
+			  WHERE profile_id=$1`
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		r.logger.With(ctx).Error("error func SelectListSelfieVerificationByProfileID, method QueryContext",
+			zap.Error(err))
+		return nil, psqlRepo.MapError(err)
+	}
+	defer rows.Close()
+	list := make([]*profile.SelfieVerificationProfile, 0)
+	for rows.Next() {
+		p := profile.SelfieVerificationProfile{}
+		err := rows.Scan(&p.ID, &p.ProfileID, &p.Pose, &p.Status, &p.FilePath, &p.Score, &p.ExpiresAt,
+			&p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.logger.With(ctx).Error("error func SelectListSelfieVerificationByProfileID, method Scan", zap.Error(err))
 			continue
 		}
 		list = append(list, &p)
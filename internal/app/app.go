@@ -17,29 +17,32 @@ type App struct {
 
 func NewApp() *App {
 	// Default logger
-	defaultLogger, err := logger.NewLogger(logger.GetDefaultLevel())
+	defaultLogger, err := logger.NewLogger(logger.GetDefaultLevel(), logger.FormatConsole, logger.Sampling{})
 	if err != nil {
-		log.Fatal("error func NewApp, method NewLogger by path internal/app/app.go", err)
+		log.Fatal("error func NewApp, method NewLogger", err)
 	}
 	// Config
 	cfg, err := config.Load(defaultLogger)
 	if err != nil {
-		log.Fatal("error func NewApp, method Load by path internal/app/app.go", err)
+		log.Fatal("error func NewApp, method Load", err)
 	}
 	// Logger level
-	loggerLevel, err := logger.NewLogger(cfg.LoggerLevel)
+	loggerLevel, err := logger.NewLogger(cfg.LoggerLevel, cfg.LoggerFormat, logger.Sampling{
+		Initial:    cfg.LoggerSamplingInitial,
+		Thereafter: cfg.LoggerSamplingAfter,
+	})
 	if err != nil {
-		log.Fatal("error func NewApp, method NewLogger by path internal/app/app.go", err)
+		log.Fatal("error func NewApp, method NewLogger", err)
 	}
 	// Database connection
 	postgresConnection, err := newPostgresConnection(cfg)
 	if err != nil {
-		log.Fatal("error func NewApp, method newPostgresConnection by path internal/app/app.go", err)
+		log.Fatal("error func NewApp, method newPostgresConnection", err)
 	}
 	database := NewDatabase(loggerLevel, postgresConnection)
 	err = postgresConnection.Ping()
 	if err != nil {
-		log.Fatal("error func NewApp, method NewDatabase by path internal/app/app.go", err)
+		log.Fatal("error func NewApp, method NewDatabase", err)
 	}
 	// Fiber
 	f := fiber.New(fiber.Config{
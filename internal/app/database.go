@@ -34,3 +34,7 @@ func newPostgresConnection(cfg *config.Config) (*sql.DB, error) {
 
 	return sql.Open("postgres", databaseURL)
 }
+
+func (db *Database) Close() error {
+	return db.psql.Close()
+}
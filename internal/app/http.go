@@ -1,11 +1,20 @@
 package app
 
 import (
+	"context"
+	healthRepo "github.com/EvgeniyBudaev/love-server/internal/adapter/psqlRepo/health"
 	profileRepo "github.com/EvgeniyBudaev/love-server/internal/adapter/psqlRepo/profile"
 	identityEntity "github.com/EvgeniyBudaev/love-server/internal/entity/identity"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/telegram"
+	healthHandler "github.com/EvgeniyBudaev/love-server/internal/handler/health"
+	openAPIHandler "github.com/EvgeniyBudaev/love-server/internal/handler/openapi"
 	profileHandler "github.com/EvgeniyBudaev/love-server/internal/handler/profile"
 	userHandler "github.com/EvgeniyBudaev/love-server/internal/handler/user"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
 	"github.com/EvgeniyBudaev/love-server/internal/middlewares"
+	"github.com/EvgeniyBudaev/love-server/internal/openapi"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	healthUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/health"
 	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
 	userUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/user"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -15,6 +24,21 @@ import (
 
 var prefix = "/api/v1"
 
+// apiVersion is the version of the api in the OpenAPI document
+const apiVersion = "1.0.0"
+
+const (
+	// exportDirectory keeps the export archives out of the static directory, they are downloaded with a token only
+	exportDirectory = "exports"
+	// selfieDirectory keeps the selfies of the verification checks out of the static directory, they are shown to
+	// the moderators only
+	selfieDirectory = "verifications"
+	// uploadDirectory keeps the uploaded images which are served as static files
+	uploadDirectory = "static/uploads"
+	// migrationDirectory keeps the migrations, the latest one is expected to be applied
+	migrationDirectory = "migrations"
+)
+
 const (
 	EMOJI_COIN       = "\U0001FA99"
 	EMOJI_SMILE      = "\U0001F642"
@@ -67,18 +91,84 @@ func (app *App) StartHTTPServer() error {
 	//	//}
 	//}
 
+	tp, err := tracing.NewTracerProvider(context.Background(), app.config)
+	if err != nil {
+		app.Logger.Fatal("error func StartHTTPServer, method NewTracerProvider",
+			zap.Error(err))
+	}
+	m := metrics.NewMetrics(app.db.psql)
+	app.fiber.Use(middlewares.NewMetricsMiddleware(m))
+	InitMetricsRoutes(app.fiber, m)
 	app.fiber.Static("/static", "./static")
 	im := identityEntity.NewIdentity(app.config, app.Logger)
-	pr := profileRepo.NewRepositoryProfile(app.Logger, app.db.psql)
+	var ip healthUseCase.IdentityPinger
+	if !app.config.HealthSkipIdentity {
+		ip = im
+	}
+	mv, err := latestMigrationVersion(migrationDirectory)
+	if err != nil {
+		app.Logger.Error("error func StartHTTPServer, method latestMigrationVersion",
+			zap.Error(err))
+	}
+	hr := healthRepo.NewRepositoryHealth(app.Logger, app.db.psql)
+	storageDirectories := []string{uploadDirectory, exportDirectory, selfieDirectory}
+	huc := healthUseCase.NewUseCaseHealth(app.Logger, hr, ip, storageDirectories, mv)
+	hh := healthHandler.NewHandlerHealth(app.Logger, huc)
+	InitHealthRoutes(app.fiber, hh)
+	pr := profileRepo.NewRepositoryProfile(app.Logger, app.db.psql, m)
 	imc := userUseCase.NewUseCaseUser(app.Logger, im)
-	puc := profileUseCase.NewUseCaseProfile(app.Logger, pr)
+	rk := profileUseCase.NewRanker(app.config.FeedRanker, app.config.FeedRankerSeed)
+	ec := profileUseCase.NewPremiumEntitlementChecker()
+	var pp profileUseCase.PaymentProvider
+	var en profileUseCase.ExportNotifier
+	var tg *telegram.Telegram
+	if app.config.TelegramBotToken != "" {
+		if tg, err = telegram.NewTelegram(app.config, app.Logger); err != nil {
+			app.Logger.Fatal("error func StartHTTPServer, method NewTelegram",
+				zap.Error(err))
+		}
+		pp = tg
+		en = tg
+	}
+	qs := profileUseCase.NewQuotaService(pr, ec, profileUseCase.DefaultQuotaLimits())
+	lp, err := profileUseCase.NewLocationPrivacy(app.config.LocationPrivacySecret)
+	if err != nil {
+		app.Logger.Fatal("error func StartHTTPServer, method NewLocationPrivacy", zap.Error(err))
+	}
+	rf := profileUseCase.NewTextReviewFilter(append(profileUseCase.DefaultStopWords(), app.config.ReviewStopWords...))
+	// No selfie comparer is plugged in yet, so the selfies are checked by the moderators alone
+	var sc profileUseCase.SelfieComparer
+	puc := profileUseCase.NewUseCaseProfile(app.Logger, pr, rk, ec, pp, qs, lp, rf, sc)
+	pe := profileUseCase.NewProfileEraser(app.Logger, pr, im)
+	exportDownloadURL := app.config.PublicURL + prefix + "/export/download"
+	pex := profileUseCase.NewProfileExporter(app.Logger, pr, en, exportDirectory, exportDownloadURL)
+	lc := NewLifecycle(app.Logger, app.fiber, app.config.Port, app.config.ShutdownTimeout)
+	if tp != nil {
+		// The closers run in reverse order, so the spans are flushed last
+		lc.OnStop("tracing", tp.Shutdown)
+	}
+	lc.Go("subscriptionExpiry", func(ctx context.Context) { app.StartSubscriptionExpiryJob(ctx, puc) })
+	lc.Go("visitRetention", func(ctx context.Context) { app.StartVisitRetentionJob(ctx, puc) })
+	lc.Go("profileErasure", func(ctx context.Context) { app.StartProfileErasureJob(ctx, pe) })
+	lc.Go("profileExport", func(ctx context.Context) { app.StartProfileExportJob(ctx, pex) })
+	if tg != nil {
+		lc.Go("telegramPayments", func(ctx context.Context) { app.StartTelegramPayments(ctx, tg, puc) })
+	}
+	lc.OnStop("database", func(ctx context.Context) error { return app.db.Close() })
 	imh := userHandler.NewHandlerUser(app.Logger, imc)
-	ph := profileHandler.NewHandlerProfile(app.Logger, puc)
+	ph := profileHandler.NewHandlerProfile(app.Logger, puc, m, selfieDirectory, exportDownloadURL)
+	doc := openapi.NewDocument("love-server", apiVersion, prefix)
 	grp := app.fiber.Group(prefix)
+	if app.config.OpenAPIValidate {
+		grp.Use(middlewares.NewContractMiddleware(doc, app.Logger))
+	}
+	InitOpenAPIRoutes(grp, openAPIHandler.NewHandlerOpenAPI(app.Logger, doc))
 	middlewares.InitFiberMiddlewares(
-		app.fiber, app.config, app.Logger, grp, imh, ph, InitPublicRoutes, InitProtectedRoutes)
-	if err := app.fiber.Listen(app.config.Port); err != nil {
-		app.Logger.Fatal("error func StartHTTPServer, method Listen by path internal/app/http.go", zap.Error(err))
+		app.fiber, app.config, app.Logger, grp, imh, ph, puc, InitPublicRoutes, InitProtectedRoutes)
+	undocumented, unregistered := doc.CompareRoutes(app.fiber.GetRoutes(true))
+	if len(undocumented) > 0 || len(unregistered) > 0 {
+		app.Logger.Warn("routes do not match the OpenAPI document",
+			zap.Strings("undocumented", undocumented), zap.Strings("unregistered", unregistered))
 	}
-	return nil
+	return lc.Run(context.Background())
 }
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	subscriptionExpiryInterval = 10 * time.Minute
+	visitRetentionInterval     = time.Hour
+	profileErasureInterval     = time.Hour
+	profileExportInterval      = time.Minute
+)
+
+// StartSubscriptionExpiryJob expires the ended subscriptions on start and then every subscriptionExpiryInterval
+func (app *App) StartSubscriptionExpiryJob(ctx context.Context, puc *profileUseCase.UseCaseProfile) {
+	runPeriodically(ctx, subscriptionExpiryInterval, func() {
+		count, err := puc.ExpireSubscriptions(ctx)
+		if err != nil {
+			app.Logger.Error("error func StartSubscriptionExpiryJob, method ExpireSubscriptions", zap.Error(err))
+		} else if count > 0 {
+			app.Logger.Info("subscriptions expired", zap.Int64("profiles", count))
+		}
+	})
+}
+
+// StartVisitRetentionJob deletes the visits older than profile.VisitRetention on start and then
+// every visitRetentionInterval
+func (app *App) StartVisitRetentionJob(ctx context.Context, puc *profileUseCase.UseCaseProfile) {
+	runPeriodically(ctx, visitRetentionInterval, func() {
+		count, err := puc.DeleteVisitBefore(ctx, time.Now().UTC().Add(-profile.VisitRetention))
+		if err != nil {
+			app.Logger.Error("error func StartVisitRetentionJob, method DeleteVisitBefore", zap.Error(err))
+		} else if count > 0 {
+			app.Logger.Info("visits deleted", zap.Int64("visits", count))
+		}
+	})
+}
+
+// StartProfileErasureJob erases the profiles deleted more than profile.DeletionGracePeriod ago on start and then
+// every profileErasureInterval
+func (app *App) StartProfileErasureJob(ctx context.Context, pe *profileUseCase.ProfileEraser) {
+	runPeriodically(ctx, profileErasureInterval, func() {
+		count, err := pe.EraseDeleted(ctx, time.Now().UTC())
+		if err != nil {
+			app.Logger.Error("error func StartProfileErasureJob, method EraseDeleted", zap.Error(err))
+		} else if count > 0 {
+			app.Logger.Info("profiles erased", zap.Int("profiles", count))
+		}
+	})
+}
+
+// StartProfileExportJob builds the pending exports and removes the expired ones on start and then
+// every profileExportInterval
+func (app *App) StartProfileExportJob(ctx context.Context, pe *profileUseCase.ProfileExporter) {
+	runPeriodically(ctx, profileExportInterval, func() {
+		count, err := pe.BuildPending(ctx, time.Now().UTC())
+		if err != nil {
+			app.Logger.Error("error func StartProfileExportJob, method BuildPending", zap.Error(err))
+		} else if count > 0 {
+			app.Logger.Info("exports built", zap.Int("exports", count))
+		}
+		count, err = pe.DeleteExpired(ctx, time.Now().UTC())
+		if err != nil {
+			app.Logger.Error("error func StartProfileExportJob, method DeleteExpired", zap.Error(err))
+		} else if count > 0 {
+			app.Logger.Info("exports deleted", zap.Int("exports", count))
+		}
+	})
+}
+
+// runPeriodically runs the job at once and then every interval until the context is done
+func runPeriodically(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
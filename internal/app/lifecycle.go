@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"go.uber.org/zap"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const defaultShutdownTimeout = 15 * time.Second
+
+// Server is the HTTP server run by the Lifecycle, fiber.App implements it
+type Server interface {
+	Listen(addr string) error
+	ShutdownWithContext(ctx context.Context) error
+}
+
+type lifecycleWorker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+type lifecycleCloser struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// Lifecycle runs the HTTP server and the background workers until SIGINT or SIGTERM is received or the server
+// fails. On shutdown the server stops accepting connections and drains the in-flight requests, then the workers
+// are cancelled and waited for and the resources are closed in the reverse order of their registration.
+// The whole shutdown is limited by shutdownTimeout.
+type Lifecycle struct {
+	logger          logger.Logger
+	server          Server
+	addr            string
+	shutdownTimeout time.Duration
+	workers         []lifecycleWorker
+	closers         []lifecycleCloser
+}
+
+func NewLifecycle(l logger.Logger, server Server, addr string, shutdownTimeout time.Duration) *Lifecycle {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	return &Lifecycle{
+		logger:          l,
+		server:          server,
+		addr:            addr,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+// Go registers the worker which is started with the server and has to return when its context is done
+func (lc *Lifecycle) Go(name string, run func(ctx context.Context)) {
+	lc.workers = append(lc.workers, lifecycleWorker{name: name, run: run})
+}
+
+// OnStop registers the resource which is closed after the server and the workers have stopped
+func (lc *Lifecycle) OnStop(name string, close func(ctx context.Context) error) {
+	lc.closers = append(lc.closers, lifecycleCloser{name: name, close: close})
+}
+
+// Run blocks until the context is done, a termination signal is received or the server fails, and then shuts
+// everything down. The error of the server and the errors of the shutdown are returned joined.
+func (lc *Lifecycle) Run(ctx context.Context) error {
+	signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	var wg sync.WaitGroup
+	for _, w := range lc.workers {
+		wg.Add(1)
+		go func(w lifecycleWorker) {
+			defer wg.Done()
+			w.run(workerCtx)
+			lc.logger.Debug("worker stopped", zap.String("worker", w.name))
+		}(w)
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- lc.server.Listen(lc.addr)
+	}()
+	var errs []error
+	isServing := true
+	select {
+	case <-signalCtx.Done():
+		lc.logger.Info("shutdown started")
+	case err := <-serveErr:
+		isServing = false
+		if err != nil {
+			lc.logger.Error("error func Run, method Listen", zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), lc.shutdownTimeout)
+	defer cancel()
+	if isServing {
+		if err := lc.server.ShutdownWithContext(shutdownCtx); err != nil {
+			lc.logger.Error("error func Run, method ShutdownWithContext", zap.Error(err))
+			errs = append(errs, err)
+		}
+		if err := <-serveErr; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	cancelWorkers()
+	if err := waitGroup(shutdownCtx, &wg); err != nil {
+		lc.logger.Error("error func Run, method waitGroup", zap.Error(err))
+		errs = append(errs, err)
+	}
+	for i := len(lc.closers) - 1; i >= 0; i-- {
+		c := lc.closers[i]
+		if err := c.close(shutdownCtx); err != nil {
+			lc.logger.Error("error func Run, method close", zap.String("resource", c.name), zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	lc.logger.Info("shutdown completed")
+	return errors.Join(errs...)
+}
+
+// waitGroup waits for the group until the context is done
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
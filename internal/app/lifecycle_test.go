@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// freeAddr returns the local address with the port which is free at the moment
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestLifecycleGracefulShutdown(t *testing.T) {
+	const (
+		handlerDelay    = 300 * time.Millisecond
+		shutdownTimeout = 5 * time.Second
+	)
+	l, err := logger.NewLogger("fatal", logger.FormatConsole, logger.Sampling{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{}, 1)
+	var isHandled atomic.Bool
+	f := fiber.New(fiber.Config{DisableStartupMessage: true})
+	f.Get("/ping", func(ctf *fiber.Ctx) error {
+		return ctf.SendString("pong")
+	})
+	f.Get("/slow", func(ctf *fiber.Ctx) error {
+		started <- struct{}{}
+		time.Sleep(handlerDelay)
+		isHandled.Store(true)
+		return ctf.SendString("done")
+	})
+	// The idle keep-alive connections would hold the shutdown until fasthttp treats them as idle
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	addr := freeAddr(t)
+	lc := NewLifecycle(l, f, addr, shutdownTimeout)
+	var isWorkerCancelled atomic.Bool
+	lc.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		isWorkerCancelled.Store(true)
+	})
+	var mu sync.Mutex
+	closed := make([]string, 0)
+	for _, name := range []string{"database", "telegram"} {
+		name := name
+		lc.OnStop(name, func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !isHandled.Load() {
+				t.Errorf("%s is closed before the in-flight request is handled", name)
+			}
+			if !isWorkerCancelled.Load() {
+				t.Errorf("%s is closed before the worker is cancelled", name)
+			}
+			closed = append(closed, name)
+			return nil
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- lc.Run(ctx)
+	}()
+	waitFor(t, "the server to listen", func() bool {
+		resp, err := client.Get("http://" + addr + "/ping")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	})
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := client.Get("http://" + addr + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+	<-started
+	shutdownStartedAt := time.Now()
+	cancel()
+
+	res := <-response
+	if res.err != nil {
+		t.Fatalf("in-flight request failed: %v", res.err)
+	}
+	if res.status != http.StatusOK || res.body != "done" {
+		t.Fatalf("unexpected in-flight response: %d %q", res.status, res.body)
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Fatalf("unexpected shutdown error: %v", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("shutdown is not completed within the timeout")
+	}
+	if elapsed := time.Since(shutdownStartedAt); elapsed > shutdownTimeout {
+		t.Fatalf("shutdown took %s, the timeout is %s", elapsed, shutdownTimeout)
+	}
+	if !isWorkerCancelled.Load() {
+		t.Fatal("worker is not cancelled")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(closed) != 2 || closed[0] != "telegram" || closed[1] != "database" {
+		t.Fatalf("closers have to run in the reverse order of the registration, got %v", closed)
+	}
+	if _, err := client.Get("http://" + addr + "/ping"); err == nil {
+		t.Fatal("server still accepts the connections after the shutdown")
+	}
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// latestMigrationVersion returns the version of the latest up migration in the directory, 0 is returned when
+// the directory does not exist
+func latestMigrationVersion(dir string) (uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var latest uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		prefix, _, isFound := strings.Cut(name, "_")
+		if !isFound {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
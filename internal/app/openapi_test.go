@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"github.com/EvgeniyBudaev/love-server/internal/config"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/pagination"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	openAPIHandler "github.com/EvgeniyBudaev/love-server/internal/handler/openapi"
+	profileHandler "github.com/EvgeniyBudaev/love-server/internal/handler/profile"
+	userHandler "github.com/EvgeniyBudaev/love-server/internal/handler/user"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
+	"github.com/EvgeniyBudaev/love-server/internal/middlewares"
+	"github.com/EvgeniyBudaev/love-server/internal/openapi"
+	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// contractStore serves the reviews and the profiles which are requested by the contract test, the other methods
+// of the store are not used
+type contractStore struct {
+	profileUseCase.Store
+}
+
+func (s *contractStore) FindById(_ context.Context, id uint64) (*profile.Profile, error) {
+	return &profile.Profile{ID: id, SessionID: "owner", IsDeleted: true}, nil
+}
+
+func (s *contractStore) FindBySessionID(_ context.Context, sessionID string) (*profile.Profile, error) {
+	return &profile.Profile{ID: 1, SessionID: sessionID}, nil
+}
+
+func (s *contractStore) UpdateLastOnline(_ context.Context, _ uint64) error {
+	return nil
+}
+
+// FindReviewById returns the published review 1 and the pending review 3 of the "owner" session
+func (s *contractStore) FindReviewById(_ context.Context, id uint64) (*profile.ResponseReviewProfile, error) {
+	status := profile.ReviewStatusPublished
+	switch id {
+	case 1:
+	case 3:
+		status = profile.ReviewStatusPending
+	default:
+		return nil, appError.NewNotFound("review is not found")
+	}
+	return &profile.ResponseReviewProfile{
+		ID: id, ProfileID: 2, HumanID: 3, Message: "hello", Rating: 4.5, Status: status,
+		CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(), SessionID: "owner",
+	}, nil
+}
+
+func (s *contractStore) SelectReviewList(
+	_ context.Context, qp *profile.QueryParamsReviewList) (*profile.ResponseListReview, error) {
+	content := []*profile.ContentReviewProfile{{
+		ID: 1, ProfileID: 2, HumanID: 3, Message: "hello", Rating: 4.5, Status: profile.ReviewStatusPublished,
+		CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+	}}
+	return &profile.ResponseListReview{
+		Pagination:    pagination.GetPagination(qp.Size, qp.Page, uint64(len(content))),
+		RatingAverage: 4.5,
+		Content:       content,
+	}, nil
+}
+
+type recordedResponse struct {
+	method string
+	route  string
+	status int
+	body   []byte
+}
+
+// newContractRouter registers the routes and the middlewares as StartHTTPServer does, the responses under the
+// prefix are recorded with the route they are matched by
+func newContractRouter(t *testing.T) (*fiber.App, *openapi.Document, func() []recordedResponse) {
+	t.Helper()
+	l, err := logger.NewLogger("fatal", logger.FormatConsole, logger.Sampling{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The JWT middleware needs the realm key, the protected routes are not requested
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{RealmRS256PublicKey: base64.StdEncoding.EncodeToString(publicKey)}
+	puc := profileUseCase.NewUseCaseProfile(l, &contractStore{}, nil, nil, nil, nil, nil, nil, nil)
+	ph := profileHandler.NewHandlerProfile(l, puc, metrics.NewMetrics(nil), t.TempDir(), "")
+	imh := userHandler.NewHandlerUser(l, nil)
+	f := fiber.New(fiber.Config{DisableStartupMessage: true})
+	doc := openapi.NewDocument("love-server", apiVersion, prefix)
+	grp := f.Group(prefix)
+	var mu sync.Mutex
+	recorded := make([]recordedResponse, 0)
+	grp.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, recordedResponse{
+			method: strings.Clone(c.Method()),
+			route:  strings.Clone(c.Route().Path),
+			status: c.Response().StatusCode(),
+			body:   append([]byte(nil), c.Response().Body()...),
+		})
+		return err
+	})
+	InitOpenAPIRoutes(grp, openAPIHandler.NewHandlerOpenAPI(l, doc))
+	middlewares.InitFiberMiddlewares(f, cfg, l, grp, imh, ph, puc, InitPublicRoutes, InitProtectedRoutes)
+	return f, doc, func() []recordedResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedResponse(nil), recorded...)
+	}
+}
+
+func TestOpenAPIDocumentMatchesRoutes(t *testing.T) {
+	f, doc, _ := newContractRouter(t)
+	undocumented, unregistered := doc.CompareRoutes(f.GetRoutes(true))
+	if len(undocumented) > 0 {
+		t.Errorf("routes are not documented: %v", undocumented)
+	}
+	if len(unregistered) > 0 {
+		t.Errorf("documented operations have no route: %v", unregistered)
+	}
+}
+
+func TestOpenAPIResponsesMatchDocument(t *testing.T) {
+	f, doc, recorded := newContractRouter(t)
+	requests := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{method: fiber.MethodGet, target: "/openapi.json", status: http.StatusOK},
+		{method: fiber.MethodGet, target: "/review/detail/1", status: http.StatusOK},
+		{method: fiber.MethodGet, target: "/review/detail/2", status: http.StatusNotFound},
+		{method: fiber.MethodGet, target: "/review/detail/abc", status: http.StatusBadRequest},
+		{method: fiber.MethodGet, target: "/review/detail/3", status: http.StatusNotFound},
+		{method: fiber.MethodGet, target: "/review/detail/3?sessionId=other", status: http.StatusNotFound},
+		{method: fiber.MethodGet, target: "/review/detail/3?sessionId=owner", status: http.StatusOK},
+		{method: fiber.MethodGet, target: "/review/list?profileId=2&page=1&size=10", status: http.StatusOK},
+		{method: fiber.MethodPost, target: "/profile/restore", body: `{}`, status: http.StatusBadRequest},
+		{method: fiber.MethodPost, target: "/profile/restore", body: `{"sessionId":"other","id":"2"}`,
+			status: http.StatusForbidden},
+		{method: fiber.MethodPost, target: "/profile/delete", body: `{}`, status: http.StatusBadRequest},
+		{method: fiber.MethodPost, target: "/profile/delete", body: `{"sessionId":"other","id":"2"}`,
+			status: http.StatusForbidden},
+	}
+	for _, rq := range requests {
+		req := httptest.NewRequest(rq.method, prefix+rq.target, strings.NewReader(rq.body))
+		if rq.body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		resp, err := f.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s %s: %v", rq.method, rq.target, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != rq.status {
+			t.Errorf("%s %s: status %d, want %d: %s", rq.method, rq.target, resp.StatusCode, rq.status, body)
+		}
+	}
+	responses := recorded()
+	if len(responses) != len(requests) {
+		t.Fatalf("expected %d recorded responses, got %d", len(requests), len(responses))
+	}
+	for _, rr := range responses {
+		if _, ok := doc.Operation(rr.method, rr.route); !ok {
+			t.Errorf("%s %s is not documented", rr.method, rr.route)
+			continue
+		}
+		if violations := doc.ValidateResponse(rr.method, rr.route, rr.status, rr.body); len(violations) > 0 {
+			t.Errorf("%s %s %d does not match the document: %v", rr.method, rr.route, rr.status, violations)
+		}
+	}
+}
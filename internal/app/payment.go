@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/telegram"
+	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"go.uber.org/zap"
+)
+
+// StartTelegramPayments handles the pre_checkout_query and successful_payment updates of the bot
+func (app *App) StartTelegramPayments(
+	ctx context.Context, tg *telegram.Telegram, puc *profileUseCase.UseCaseProfile) {
+	updates := tg.GetUpdatesChan()
+	for {
+		select {
+		case <-ctx.Done():
+			tg.StopReceivingUpdates()
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if q := update.PreCheckoutQuery; q != nil {
+				checkout := &profile.CheckoutPayment{
+					Payload:     q.InvoicePayload,
+					Currency:    q.Currency,
+					TotalAmount: q.TotalAmount,
+				}
+				errorMessage := ""
+				if err := puc.CheckPayment(ctx, checkout); err != nil {
+					app.Logger.Error("error func StartTelegramPayments, method CheckPayment", zap.Error(err))
+					errorMessage = "Платёж не может быть принят, попробуйте ещё раз"
+				}
+				if err := tg.AnswerPreCheckoutQuery(q.ID, errorMessage); err != nil {
+					app.Logger.Error("error func StartTelegramPayments, method AnswerPreCheckoutQuery", zap.Error(err))
+				}
+				continue
+			}
+			if update.Message == nil || update.Message.SuccessfulPayment == nil {
+				continue
+			}
+			sp := update.Message.SuccessfulPayment
+			checkout := &profile.CheckoutPayment{
+				Payload:                 sp.InvoicePayload,
+				Currency:                sp.Currency,
+				TotalAmount:             sp.TotalAmount,
+				TelegramPaymentChargeID: sp.TelegramPaymentChargeID,
+				ProviderPaymentChargeID: sp.ProviderPaymentChargeID,
+			}
+			subscription, err := puc.CompletePayment(ctx, checkout)
+			if err != nil {
+				app.Logger.Error("error func StartTelegramPayments, method CompletePayment", zap.Error(err))
+				continue
+			}
+			text := fmt.Sprintf("Премиум активирован до %s "+EMOJI_SUNGLASSES,
+				subscription.EndedAt.Format("02.01.2006"))
+			if err := tg.SendMessage(update.Message.Chat.ID, text); err != nil {
+				app.Logger.Error("error func StartTelegramPayments, method SendMessage", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/config"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/telegram"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBotAPI serves the methods of the Telegram Bot API which are used by the payments, the updates are returned
+// by getUpdates in the order they are pushed
+type fakeBotAPI struct {
+	mu       sync.Mutex
+	updates  []map[string]interface{}
+	invoices []url.Values
+	answers  []url.Values
+	messages []url.Values
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var result interface{}
+	switch path.Base(r.URL.Path) {
+	case "getMe":
+		result = map[string]interface{}{"id": 1, "is_bot": true, "first_name": "love", "username": "love_bot"}
+	case "createInvoiceLink":
+		f.record(&f.invoices, r.Form)
+		result = "https://t.me/$invoice-" + r.Form.Get("payload")
+	case "answerPreCheckoutQuery":
+		f.record(&f.answers, r.Form)
+		result = true
+	case "sendMessage":
+		f.record(&f.messages, r.Form)
+		chatID, _ := strconv.ParseInt(r.Form.Get("chat_id"), 10, 64)
+		result = map[string]interface{}{"message_id": 1, "date": 0, "chat": map[string]interface{}{"id": chatID}}
+	case "getUpdates":
+		offset, _ := strconv.Atoi(r.Form.Get("offset"))
+		result = f.pending(offset)
+	default:
+		http.Error(w, "unknown method", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+func (f *fakeBotAPI) record(list *[]url.Values, form url.Values) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	*list = append(*list, form)
+}
+
+// pending returns the updates from the offset, the long polling is imitated by a short wait when there are none
+func (f *fakeBotAPI) pending(offset int) []map[string]interface{} {
+	f.mu.Lock()
+	list := make([]map[string]interface{}, 0)
+	for i, u := range f.updates {
+		if i+1 >= offset {
+			list = append(list, u)
+		}
+	}
+	f.mu.Unlock()
+	if len(list) == 0 {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return list
+}
+
+func (f *fakeBotAPI) push(update map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	update["update_id"] = len(f.updates) + 1
+	f.updates = append(f.updates, update)
+}
+
+func (f *fakeBotAPI) count(list *[]url.Values) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(*list)
+}
+
+func (f *fakeBotAPI) get(list *[]url.Values, i int) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return (*list)[i]
+}
+
+// paymentStore keeps the payment ledger and the subscriptions in memory, the other methods of the store are not
+// used by the payments
+type paymentStore struct {
+	profileUseCase.Store
+	mu            sync.Mutex
+	payments      []*profile.PaymentProfile
+	subscriptions []*profile.SubscriptionProfile
+}
+
+func (s *paymentStore) AddPayment(_ context.Context, p *profile.PaymentProfile) (*profile.PaymentProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.ID = uint64(len(s.payments) + 1)
+	payment := *p
+	s.payments = append(s.payments, &payment)
+	return p, nil
+}
+
+func (s *paymentStore) FindPaymentByPayload(
+	_ context.Context, payload string) (*profile.PaymentProfile, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.payments {
+		if p.Payload == payload {
+			payment := *p
+			return &payment, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *paymentStore) CompletePayment(_ context.Context, p *profile.PaymentProfile,
+	subscription *profile.SubscriptionProfile) (*profile.SubscriptionProfile, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, payment := range s.payments {
+		if payment.ID != p.ID {
+			continue
+		}
+		if payment.Status != profile.PaymentStatusPending {
+			return nil, false, nil
+		}
+		*payment = *p
+		subscription.ID = uint64(len(s.subscriptions) + 1)
+		s.subscriptions = append(s.subscriptions, subscription)
+		return subscription, true, nil
+	}
+	return nil, false, nil
+}
+
+func (s *paymentStore) FindActiveSubscriptionByProfileID(
+	_ context.Context, profileID uint64) (*profile.SubscriptionProfile, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.subscriptions) - 1; i >= 0; i-- {
+		if sub := s.subscriptions[i]; sub.ProfileID == profileID && sub.EndedAt.After(time.Now().UTC()) {
+			return sub, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *paymentStore) payment(i int) profile.PaymentProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.payments[i]
+}
+
+func (s *paymentStore) subscriptionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscriptions)
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTelegramPayments(t *testing.T) {
+	const (
+		profileID = 7
+		chatID    = 42
+	)
+	api := &fakeBotAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	l, err := logger.NewLogger("fatal", logger.FormatConsole, logger.Sampling{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{TelegramBotToken: "test", TelegramAPIEndpoint: server.URL + "/bot%s/%s"}
+	tg, err := telegram.NewTelegram(cfg, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &paymentStore{}
+	puc := profileUseCase.NewUseCaseProfile(l, store, nil, nil, tg, nil, nil, nil, nil)
+
+	invoice, err := puc.CreateInvoice(
+		context.Background(), profileID, profile.SubscriptionPlanPremium, profile.SubscriptionPeriodMonth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount := profile.SubscriptionPrices[profile.SubscriptionPeriodMonth]
+	payment := store.payment(0)
+	if payment.Status != profile.PaymentStatusPending || payment.Amount != amount ||
+		payment.Currency != profile.PaymentCurrencyStars || payment.ProfileID != profileID {
+		t.Fatalf("unexpected pending payment: %+v", payment)
+	}
+	if api.count(&api.invoices) != 1 {
+		t.Fatalf("expected one createInvoiceLink call, got %d", api.count(&api.invoices))
+	}
+	params := api.get(&api.invoices, 0)
+	if params.Get("payload") != payment.Payload || params.Get("currency") != profile.PaymentCurrencyStars {
+		t.Fatalf("unexpected invoice params: %v", params)
+	}
+	if want := fmt.Sprintf(`[{"label":"%s","amount":%d}]`, "Премиум", amount); params.Get("prices") != want {
+		t.Fatalf("unexpected invoice prices: %s, want %s", params.Get("prices"), want)
+	}
+	if invoice.ID != payment.ID || invoice.InvoiceLink != "https://t.me/$invoice-"+payment.Payload {
+		t.Fatalf("unexpected invoice: %+v", invoice)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	app := &App{Logger: l}
+	go func() {
+		defer close(done)
+		app.StartTelegramPayments(ctx, tg, puc)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	from := map[string]interface{}{"id": chatID, "is_bot": false, "first_name": "user"}
+	api.push(map[string]interface{}{"pre_checkout_query": map[string]interface{}{
+		"id": "wrong-amount", "from": from, "currency": profile.PaymentCurrencyStars,
+		"total_amount": amount - 1, "invoice_payload": payment.Payload,
+	}})
+	api.push(map[string]interface{}{"pre_checkout_query": map[string]interface{}{
+		"id": "checkout", "from": from, "currency": profile.PaymentCurrencyStars,
+		"total_amount": amount, "invoice_payload": payment.Payload,
+	}})
+	waitFor(t, "pre_checkout_query answers", func() bool { return api.count(&api.answers) == 2 })
+	if answer := api.get(&api.answers, 0); answer.Get("pre_checkout_query_id") != "wrong-amount" ||
+		answer.Get("ok") == "true" || answer.Get("error_message") == "" {
+		t.Fatalf("checkout with a wrong amount has to be rejected: %v", answer)
+	}
+	if answer := api.get(&api.answers, 1); answer.Get("pre_checkout_query_id") != "checkout" ||
+		answer.Get("ok") != "true" {
+		t.Fatalf("checkout has to be confirmed: %v", answer)
+	}
+	if store.payment(0).Status != profile.PaymentStatusPending || store.subscriptionCount() != 0 {
+		t.Fatal("pre_checkout_query must not complete the payment")
+	}
+
+	successfulPayment := func() map[string]interface{} {
+		return map[string]interface{}{"message": map[string]interface{}{
+			"message_id": 1, "date": 0, "from": from,
+			"chat": map[string]interface{}{"id": chatID, "type": "private"},
+			"successful_payment": map[string]interface{}{
+				"currency": profile.PaymentCurrencyStars, "total_amount": amount,
+				"invoice_payload": payment.Payload, "telegram_payment_charge_id": "tg-charge",
+				"provider_payment_charge_id": "provider-charge",
+			},
+		}}
+	}
+	api.push(successfulPayment())
+	// The replayed update must not prolong the subscription, the checkout after it shows that it has been handled
+	api.push(successfulPayment())
+	api.push(map[string]interface{}{"pre_checkout_query": map[string]interface{}{
+		"id": "after-replay", "from": from, "currency": profile.PaymentCurrencyStars,
+		"total_amount": amount, "invoice_payload": payment.Payload,
+	}})
+	waitFor(t, "the update after the replay", func() bool { return api.count(&api.answers) == 3 })
+
+	paid := store.payment(0)
+	if paid.Status != profile.PaymentStatusPaid || paid.TelegramPaymentChargeID != "tg-charge" ||
+		paid.ProviderPaymentChargeID != "provider-charge" {
+		t.Fatalf("unexpected paid payment: %+v", paid)
+	}
+	if store.subscriptionCount() != 1 {
+		t.Fatalf("expected one subscription, got %d", store.subscriptionCount())
+	}
+	subscription, isExist, _ := store.FindActiveSubscriptionByProfileID(context.Background(), profileID)
+	if !isExist || subscription.Period != profile.SubscriptionPeriodMonth ||
+		subscription.Status != profile.SubscriptionStatusActive {
+		t.Fatalf("unexpected subscription: %+v", subscription)
+	}
+	if api.count(&api.messages) != 1 || api.get(&api.messages, 0).Get("chat_id") != strconv.Itoa(chatID) {
+		t.Fatalf("expected one confirmation message to the chat, got %d", api.count(&api.messages))
+	}
+	if answer := api.get(&api.answers, 2); answer.Get("ok") == "true" {
+		t.Fatalf("checkout of the paid payment has to be rejected: %v", answer)
+	}
+}
@@ -1,11 +1,35 @@
 package app
 
 import (
+	"github.com/EvgeniyBudaev/love-server/internal/handler/health"
+	"github.com/EvgeniyBudaev/love-server/internal/handler/openapi"
 	"github.com/EvgeniyBudaev/love-server/internal/handler/profile"
 	"github.com/EvgeniyBudaev/love-server/internal/handler/user"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
+	"github.com/EvgeniyBudaev/love-server/internal/middlewares"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 )
 
+const roleAdmin = "admin"
+
+// InitMetricsRoutes registers the prometheus endpoint outside of the api prefix
+func InitMetricsRoutes(app *fiber.App, m *metrics.Metrics) {
+	app.Get("/metrics", adaptor.HTTPHandler(m.Handler()))
+}
+
+// InitHealthRoutes registers the probes outside of the api prefix, before the rate limit and the JWT middlewares
+func InitHealthRoutes(app *fiber.App, hh *health.HandlerHealth) {
+	app.Get("/healthz", hh.GetLivenessHandler())
+	app.Get("/readyz", hh.GetReadinessHandler())
+}
+
+// InitOpenAPIRoutes registers the OpenAPI document of the api, before the rate limit and the JWT middlewares
+func InitOpenAPIRoutes(grp fiber.Router, oh *openapi.HandlerOpenAPI) {
+	grp.Get("/openapi.json", oh.GetDocumentHandler())
+}
+
 func InitPublicRoutes(grp fiber.Router, imh *user.HandlerUser, ph *profile.HandlerProfile) {
 	grp.Post("/user/register", imh.PostRegisterHandler())
 	grp.Put("/user/update", imh.UpdateUserHandler())
@@ -17,6 +41,7 @@ func InitPublicRoutes(grp fiber.Router, imh *user.HandlerUser, ph *profile.Handl
 	grp.Get("/profile/detail/:id", ph.GetProfileDetailHandler())
 	grp.Post("/profile/edit", ph.UpdateProfileHandler())
 	grp.Post("/profile/delete", ph.DeleteProfileHandler())
+	grp.Post("/profile/restore", ph.RestoreProfileHandler())
 	grp.Post("/profile/image/delete", ph.DeleteProfileImageHandler())
 
 	grp.Post("/review/add", ph.AddReviewHandler())
@@ -24,16 +49,60 @@ func InitPublicRoutes(grp fiber.Router, imh *user.HandlerUser, ph *profile.Handl
 	grp.Post("/review/delete", ph.DeleteReviewHandler())
 	grp.Get("/review/list", ph.GetReviewListHandler())
 	grp.Get("/review/detail/:id", ph.GetReviewByIDHandler())
+	grp.Post("/review/report", ph.AddReviewReportHandler())
 
 	grp.Post("/like/add", ph.AddLikeHandler())
 	grp.Put("/like/update", ph.UpdateLikeHandler())
 	grp.Post("/like/delete", ph.DeleteLikeHandler())
+	grp.Get("/like/incoming", ph.GetIncomingLikeListHandler())
+	grp.Get("/like/outgoing", ph.GetOutgoingLikeListHandler())
+	grp.Get("/like/history", ph.GetLikeHistoryHandler())
+
+	grp.Post("/swipe/add", ph.AddSwipeHandler())
+	grp.Post("/swipe/rewind", ph.RewindSwipeHandler())
+
+	grp.Get("/subscription/session/:id", ph.GetSubscriptionBySessionIDHandler())
+
+	grp.Post("/payment/add", ph.AddPaymentHandler())
+
+	grp.Get("/visit/list", ph.GetVisitListHandler())
+
+	grp.Post("/export/add", ph.AddExportHandler())
+	grp.Get("/export/detail/:id", ph.GetExportByIDHandler())
+	grp.Get("/export/download/:id", ph.DownloadExportHandler())
 
 	grp.Post("/block/add", ph.AddBlockHandler())
 	grp.Put("/block/update", ph.UpdateBlockHandler())
+	grp.Post("/block/delete", ph.DeleteBlockHandler())
+	grp.Get("/block/list", ph.GetBlockListHandler())
 
 	grp.Post("/complaint/add", ph.AddComplaintHandler())
+
+	grp.Post("/profile/verification/challenge", ph.AddSelfieChallengeHandler())
+	grp.Post("/profile/verification/selfie", ph.AddSelfieHandler())
 }
 
-func InitProtectedRoutes(grp fiber.Router, ph *profile.HandlerProfile) {
+func InitProtectedRoutes(grp fiber.Router, l logger.Logger, ph *profile.HandlerProfile) {
+	grp.Post("/subscription/add", middlewares.NewRequiresRealmRole(roleAdmin, l), ph.AddSubscriptionHandler())
+
+	grp.Get("/review/moderation/list", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.GetReviewModerationListHandler())
+	grp.Post("/review/approve", middlewares.NewRequiresRealmRole(roleAdmin, l), ph.ApproveReviewHandler())
+	grp.Post("/review/reject", middlewares.NewRequiresRealmRole(roleAdmin, l), ph.RejectReviewHandler())
+
+	grp.Get("/profile/age/moderation/list", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.GetAgeVerificationListHandler())
+	grp.Post("/profile/age/confirm", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.ConfirmAgeVerificationHandler())
+	grp.Post("/profile/age/reject", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.RejectAgeVerificationHandler())
+
+	grp.Get("/profile/verification/moderation/list", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.GetSelfieModerationListHandler())
+	grp.Get("/profile/verification/selfie/:id", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.GetSelfieHandler())
+	grp.Post("/profile/verification/approve", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.ApproveSelfieHandler())
+	grp.Post("/profile/verification/reject", middlewares.NewRequiresRealmRole(roleAdmin, l),
+		ph.RejectSelfieHandler())
 }
@@ -5,39 +5,57 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	"time"
 )
 
 type Config struct {
-	Port                string `envconfig:"PORT"`
-	LoggerLevel         string `envconfig:"LOGGER_LEVEL"`
-	Host                string `envconfig:"HOST"`
-	DBPort              string `envconfig:"DB_PORT"`
-	DBUser              string `envconfig:"DB_USER"`
-	DBPassword          string `envconfig:"DB_PASSWORD"`
-	DBName              string `envconfig:"DB_NAME"`
-	DBSSlMode           string `envconfig:"DB_SSLMODE"`
-	TelegramBotToken    string `envconfig:"TELEGRAM_BOT_TOKEN"`
-	JWTSecret           string `envconfig:"JWT_SECRET"`
-	JWTIssuer           string `envconfig:"JWT_ISSUER"`
-	JWTAudience         string `envconfig:"JWT_AUDIENCE"`
-	CookieDomain        string `envconfig:"COOKIE_DOMAIN"`
-	Domain              string `envconfig:"DOMAIN"`
-	BaseUrl             string `envconfig:"KEYCLOAK_BASE_URL"`
-	Realm               string `envconfig:"KEYCLOAK_REALM"`
-	ClientId            string `envconfig:"KEYCLOAK_CLIENT_ID"`
-	ClientSecret        string `envconfig:"KEYCLOAK_CLIENT_SECRET"`
-	RealmRS256PublicKey string `envconfig:"KEYCLOAK_REALM_RS256_PUBLIC_KEY"`
+	Port                  string        `envconfig:"PORT"`
+	ShutdownTimeout       time.Duration `envconfig:"SHUTDOWN_TIMEOUT"`
+	LoggerLevel           string        `envconfig:"LOGGER_LEVEL"`
+	LoggerFormat          string        `envconfig:"LOGGER_FORMAT"`
+	LoggerSamplingInitial int           `envconfig:"LOGGER_SAMPLING_INITIAL"`
+	LoggerSamplingAfter   int           `envconfig:"LOGGER_SAMPLING_THEREAFTER"`
+	Host                  string        `envconfig:"HOST"`
+	DBPort                string        `envconfig:"DB_PORT"`
+	DBUser                string        `envconfig:"DB_USER"`
+	DBPassword            string        `envconfig:"DB_PASSWORD"`
+	DBName                string        `envconfig:"DB_NAME"`
+	DBSSlMode             string        `envconfig:"DB_SSLMODE"`
+	TelegramBotToken      string        `envconfig:"TELEGRAM_BOT_TOKEN"`
+	TelegramAPIEndpoint   string        `envconfig:"TELEGRAM_API_ENDPOINT"`
+	PublicURL             string        `envconfig:"PUBLIC_URL"`
+	JWTSecret             string        `envconfig:"JWT_SECRET"`
+	JWTIssuer             string        `envconfig:"JWT_ISSUER"`
+	JWTAudience           string        `envconfig:"JWT_AUDIENCE"`
+	CookieDomain          string        `envconfig:"COOKIE_DOMAIN"`
+	Domain                string        `envconfig:"DOMAIN"`
+	BaseUrl               string        `envconfig:"KEYCLOAK_BASE_URL"`
+	Realm                 string        `envconfig:"KEYCLOAK_REALM"`
+	ClientId              string        `envconfig:"KEYCLOAK_CLIENT_ID"`
+	ClientSecret          string        `envconfig:"KEYCLOAK_CLIENT_SECRET"`
+	RealmRS256PublicKey   string        `envconfig:"KEYCLOAK_REALM_RS256_PUBLIC_KEY"`
+	RateLimitMax          int           `envconfig:"RATE_LIMIT_MAX"`
+	RateLimitExpiration   time.Duration `envconfig:"RATE_LIMIT_EXPIRATION"`
+	LocationPrivacySecret string        `envconfig:"LOCATION_PRIVACY_SECRET"`
+	ReviewStopWords       []string      `envconfig:"REVIEW_STOP_WORDS"`
+	HealthSkipIdentity    bool          `envconfig:"HEALTH_SKIP_IDENTITY"`
+	FeedRanker            string        `envconfig:"FEED_RANKER"`
+	FeedRankerSeed        int64         `envconfig:"FEED_RANKER_SEED"`
+	TracingExporter       string        `envconfig:"TRACING_EXPORTER"`
+	TracingEndpoint       string        `envconfig:"TRACING_ENDPOINT"`
+	TracingSampleRatio    float64       `envconfig:"TRACING_SAMPLE_RATIO"`
+	OpenAPIValidate       bool          `envconfig:"OPENAPI_VALIDATE_RESPONSES"`
 }
 
 func Load(l logger.Logger) (*Config, error) {
 	var cfg Config
 	if err := godotenv.Load(); err != nil {
-		l.Debug("error func Load, method Load by path internal/config/config.go", zap.Error(err))
+		l.Error("error func Load, method Load", zap.Error(err))
 		return nil, err
 	}
 	err := envconfig.Process("MYAPP", &cfg)
 	if err != nil {
-		l.Debug("error func Load, method Process by path internal/config/config.go", zap.Error(err))
+		l.Error("error func Load, method Process", zap.Error(err))
 		return nil, err
 	}
 	return &cfg, nil
@@ -0,0 +1,88 @@
+package appError
+
+import (
+	"errors"
+)
+
+// Kind classifies the error, the response status and the error code are derived from it
+type Kind string
+
+const (
+	KindNotFound   Kind = "NOT_FOUND"
+	KindConflict   Kind = "CONFLICT"
+	KindForbidden  Kind = "FORBIDDEN"
+	KindValidation Kind = "VALIDATION"
+	KindInternal   Kind = "INTERNAL"
+)
+
+// internalMessage is returned to the client instead of the message of the internal error
+const internalMessage = "internal error"
+
+// FieldError describes the invalid field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  []*FieldError
+	// Err is the cause, it is logged but is not returned to the client
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewNotFound(message string) error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func NewConflict(message string) error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+func NewForbidden(message string) error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func NewValidation(message string, fields ...*FieldError) error {
+	return &Error{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+// NewInternal wraps the error which the client can't fix, e.g. the database is down
+func NewInternal(err error) error {
+	return &Error{Kind: KindInternal, Message: internalMessage, Err: err}
+}
+
+// Wrap returns the error of the kind with the cause err
+func Wrap(err error, kind Kind, message string) error {
+	if kind == KindInternal {
+		return NewInternal(err)
+	}
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// As returns the domain error in the chain of err
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// Is reports whether err is the domain error of the kind
+func Is(err error, kind Kind) bool {
+	e, ok := As(err)
+	return ok && e.Kind == kind
+}
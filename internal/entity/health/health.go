@@ -0,0 +1,35 @@
+package health
+
+const (
+	StatusUp      = "up"
+	StatusDown    = "down"
+	StatusSkipped = "skipped"
+)
+
+const (
+	CheckDatabase  = "database"
+	CheckStorage   = "storage"
+	CheckIdentity  = "identity"
+	CheckMigration = "migration"
+)
+
+type ResponseLiveness struct {
+	Status string `json:"status"`
+}
+
+type ResponseCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ResponseReadiness struct {
+	Status string           `json:"status"`
+	Checks []*ResponseCheck `json:"checks"`
+}
+
+type MigrationVersion struct {
+	Version uint64
+	Dirty   bool
+}
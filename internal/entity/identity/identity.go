@@ -34,7 +34,7 @@ func (i *Identity) loginRestApiClient(ctx context.Context) (*gocloak.JWT, error)
 	client := gocloak.NewClient(i.BaseUrl)
 	token, err := client.LoginClient(ctx, i.ClientId, i.ClientSecret, i.Realm)
 	if err != nil {
-		i.logger.Debug("error unable to login the rest client by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to login the rest client", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to login the rest client")
 	}
 	return token, nil
@@ -48,41 +48,39 @@ func (i *Identity) CreateUser(ctx context.Context, user gocloak.User, password s
 	client := gocloak.NewClient(i.BaseUrl)
 	isUniqueMobileNumber, err := i.validateMobileNumbers(ctx, (*user.Attributes)["mobileNumber"], token, client)
 	if err != nil {
-		i.logger.Debug("error get users for validation mobile number is invalid by path"+
-			" entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Warn("error get users for validation mobile number is invalid", zap.Error(err))
 		return nil, errors.Wrap(err, "get users for validation mobile number is invalid")
 	}
 	if !isUniqueMobileNumber {
-		i.logger.Debug("error mobile number must be unique by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Warn("error mobile number must be unique", zap.Error(err))
 		return nil, errors.New("mobile number must be unique")
 	}
 	userId, err := client.CreateUser(ctx, token.AccessToken, i.Realm, user)
 	if err != nil {
-		i.logger.Debug("error unable to create the user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to create the user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to create the user")
 	}
 	err = client.SetPassword(ctx, token.AccessToken, userId, i.Realm, password, false)
 	if err != nil {
-		i.logger.Debug("error unable to set the password for the user by path"+
-			" entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to set the password for the user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to set the password for the user")
 	}
 	var roleNameLowerCase = strings.ToLower(role)
 	roleKeycloak, err := client.GetRealmRole(ctx, token.AccessToken, i.Realm, roleNameLowerCase)
 	if err != nil {
-		i.logger.Debug("error unable to get role by name by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to get role by name", zap.Error(err))
 		return nil, errors.Wrap(err, fmt.Sprintf("unable to get role by name: '%v'", roleNameLowerCase))
 	}
 	err = client.AddRealmRoleToUser(ctx, token.AccessToken, i.Realm, userId, []gocloak.Role{
 		*roleKeycloak,
 	})
 	if err != nil {
-		i.logger.Debug("error unable to add a realm role to user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to add a realm role to user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to add a realm role to user")
 	}
 	userKeycloak, err := client.GetUserByID(ctx, token.AccessToken, i.Realm, userId)
 	if err != nil {
-		i.logger.Debug("error unable to get recently created user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to get recently created user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to get recently created user")
 	}
 	return userKeycloak, nil
@@ -96,17 +94,16 @@ func (i *Identity) UpdateUser(ctx context.Context, user gocloak.User) (*gocloak.
 	client := gocloak.NewClient(i.BaseUrl)
 	isUniqueMobileNumber, err := i.validateMobileNumbers(ctx, (*user.Attributes)["mobileNumber"], token, client)
 	if err != nil {
-		i.logger.Debug("error get users for validation mobile number is invalid by path"+
-			" entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Warn("error get users for validation mobile number is invalid", zap.Error(err))
 		return nil, errors.Wrap(err, "get users for validation mobile number is invalid")
 	}
 	if !isUniqueMobileNumber {
-		i.logger.Debug("error mobile number must be unique by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Warn("error mobile number must be unique", zap.Error(err))
 		return nil, errors.New("mobile number must be unique")
 	}
 	err = client.UpdateUser(ctx, token.AccessToken, i.Realm, user)
 	if err != nil {
-		i.logger.Debug("error unable to update the user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to update the user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to update the user")
 	}
 	if user.ID == nil {
@@ -114,7 +111,7 @@ func (i *Identity) UpdateUser(ctx context.Context, user gocloak.User) (*gocloak.
 	}
 	userKeycloak, err := client.GetUserByID(ctx, token.AccessToken, i.Realm, *user.ID)
 	if err != nil {
-		i.logger.Debug("error unable to get recently created user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to get recently created user", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to get recently created user")
 	}
 	return userKeycloak, nil
@@ -123,22 +120,33 @@ func (i *Identity) UpdateUser(ctx context.Context, user gocloak.User) (*gocloak.
 func (i *Identity) DeleteUser(ctx context.Context, user gocloak.User) error {
 	token, err := i.loginRestApiClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 	client := gocloak.NewClient(i.BaseUrl)
 	err = client.DeleteUser(ctx, token.AccessToken, i.Realm, *user.ID)
 	if err != nil {
-		i.logger.Debug("error unable to delete the user by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to delete the user", zap.Error(err))
 		return errors.Wrap(err, "unable to delete the user")
 	}
 	return nil
 }
 
+// Ping checks that the realm of the identity provider is reachable, no login is needed
+func (i *Identity) Ping(ctx context.Context) error {
+	client := gocloak.NewClient(i.BaseUrl)
+	_, err := client.GetIssuer(ctx, i.Realm)
+	if err != nil {
+		i.logger.With(ctx).Error("error unable to get the issuer", zap.Error(err))
+		return errors.Wrap(err, "unable to get the issuer")
+	}
+	return nil
+}
+
 func (i *Identity) RetrospectToken(ctx context.Context, accessToken string) (*gocloak.IntroSpectTokenResult, error) {
 	client := gocloak.NewClient(i.BaseUrl)
 	rptResult, err := client.RetrospectToken(ctx, accessToken, i.ClientId, i.ClientSecret, i.Realm)
 	if err != nil {
-		i.logger.Debug("error unable to retrospect token by path entity/identity/identity.go", zap.Error(err))
+		i.logger.With(ctx).Error("error unable to retrospect token", zap.Error(err))
 		return nil, errors.Wrap(err, "unable to retrospect token")
 	}
 	return rptResult, nil
@@ -32,7 +32,12 @@ func ApplyPagination(sqlQuery string, page uint64, size uint64) string {
 	return sqlQuery
 }
 
-func GetTotalItems(ctx context.Context, db *sql.DB, sqlQuery string, args ...interface{}) (uint64, error) {
+// RowQueryer runs the query which returns a single row, *sql.DB implements it
+type RowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func GetTotalItems(ctx context.Context, db RowQueryer, sqlQuery string, args ...interface{}) (uint64, error) {
 	var totalItems uint64
 	err := db.QueryRowContext(ctx, sqlQuery, args...).Scan(&totalItems)
 	if err != nil {
@@ -59,3 +64,19 @@ func GetPagination(size uint64, page uint64, totalItems uint64) *Pagination {
 	})
 	return paging
 }
+
+// GetSliceBounds returns the start and end indexes of the page in a list of totalItems elements
+func GetSliceBounds(page uint64, size uint64, totalItems uint64) (uint64, uint64) {
+	if page == 0 {
+		page = 1
+	}
+	start := (page - 1) * size
+	if start > totalItems {
+		start = totalItems
+	}
+	end := start + size
+	if end > totalItems {
+		end = totalItems
+	}
+	return start, end
+}
@@ -1,7 +1,11 @@
 package profile
 
 import (
+	"fmt"
 	"github.com/EvgeniyBudaev/love-server/internal/entity/pagination"
+	"math"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -20,6 +24,7 @@ type Profile struct {
 	IsPremium      bool                      `json:"isPremium"`
 	IsShowDistance bool                      `json:"isShowDistance"`
 	IsInvisible    bool                      `json:"isInvisible"`
+	IsVerified     bool                      `json:"isVerified"`
 	CreatedAt      time.Time                 `json:"createdAt"`
 	UpdatedAt      time.Time                 `json:"updatedAt"`
 	LastOnline     time.Time                 `json:"lastOnline"`
@@ -29,66 +34,103 @@ type Profile struct {
 	Filter         *FilterProfile            `json:"filters"`
 }
 
+// MinAge is the minimum age of the profile owner
+const MinAge = 18
+
+// RequestAddProfile is checked by the validate tags before the profile is created. The coordinates are kept as
+// strings, because the empty form value can't be told from zero otherwise.
 type RequestAddProfile struct {
-	SessionID        string    `json:"sessionId"`
-	UserName         string    `json:"userName"`
-	DisplayName      string    `json:"displayName"`
-	Birthday         time.Time `json:"birthday"`
-	Gender           string    `json:"gender"`
-	SearchGender     string    `json:"searchGender"`
-	Location         string    `json:"location"`
-	Description      string    `json:"description"`
-	Height           string    `json:"height"`
-	Weight           string    `json:"weight"`
-	LookingFor       string    `json:"lookingFor"`
-	TelegramID       string    `json:"telegramId"`
+	SessionID        string    `json:"sessionId" validate:"required"`
+	UserName         string    `json:"userName" validate:"required"`
+	DisplayName      string    `json:"displayName" validate:"required,max=64"`
+	Birthday         time.Time `json:"birthday" validate:"required,lte,adult"`
+	Gender           string    `json:"gender" validate:"max=32"`
+	SearchGender     string    `json:"searchGender" validate:"max=32"`
+	Location         string    `json:"location" validate:"max=255"`
+	Description      string    `json:"description" validate:"max=2000"`
+	Height           uint8     `json:"height" validate:"omitempty,gte=100,lte=250"`
+	Weight           uint8     `json:"weight" validate:"omitempty,gte=30,lte=250"`
+	LookingFor       string    `json:"lookingFor" validate:"max=64"`
+	TelegramID       uint64    `json:"telegramId" validate:"required"`
 	TelegramUserName string    `json:"telegramUserName"`
 	Firstname        string    `json:"firstName"`
 	Lastname         string    `json:"lastName"`
 	LanguageCode     string    `json:"languageCode"`
-	AllowsWriteToPm  string    `json:"allowsWriteToPm"`
+	AllowsWriteToPm  bool      `json:"allowsWriteToPm"`
 	QueryID          string    `json:"queryId"`
-	Latitude         string    `json:"latitude"`
-	Longitude        string    `json:"longitude"`
-	AgeFrom          string    `json:"ageFrom"`
-	AgeTo            string    `json:"ageTo"`
-	Distance         string    `json:"distance"`
-	Page             string    `json:"page"`
-	Size             string    `json:"size"`
+	Latitude         string    `json:"latitude" validate:"required,latitude"`
+	Longitude        string    `json:"longitude" validate:"required,longitude"`
+	AgeFrom          uint8     `json:"ageFrom" validate:"omitempty,gte=18,lte=100"`
+	AgeTo            uint8     `json:"ageTo" validate:"omitempty,gte=18,lte=100,gtefield=AgeFrom"`
+	Distance         uint64    `json:"distance" validate:"omitempty,gte=1000,lte=20000000"`
+	Page             uint64    `json:"page"`
+	Size             uint64    `json:"size"`
 	Image            []byte    `json:"image"`
 }
 
+// RequestUpdateProfile keeps the current location when both coordinates are empty and the current invisible mode
+// when isInvisible is empty
 type RequestUpdateProfile struct {
-	ID               string    `json:"id"`
-	UserName         string    `json:"userName"`
-	DisplayName      string    `json:"displayName"`
-	Birthday         time.Time `json:"birthday"`
-	Gender           string    `json:"gender"`
-	SearchGender     string    `json:"searchGender"`
-	Location         string    `json:"location"`
-	Description      string    `json:"description"`
-	Height           string    `json:"height"`
-	Weight           string    `json:"weight"`
-	LookingFor       string    `json:"lookingFor"`
-	TelegramID       string    `json:"telegramId"`
+	ID               uint64    `json:"id" validate:"required"`
+	UserName         string    `json:"userName" validate:"required"`
+	DisplayName      string    `json:"displayName" validate:"required,max=64"`
+	Birthday         time.Time `json:"birthday" validate:"required,lte,adult"`
+	Gender           string    `json:"gender" validate:"max=32"`
+	SearchGender     string    `json:"searchGender" validate:"max=32"`
+	Location         string    `json:"location" validate:"max=255"`
+	Description      string    `json:"description" validate:"max=2000"`
+	Height           uint8     `json:"height" validate:"omitempty,gte=100,lte=250"`
+	Weight           uint8     `json:"weight" validate:"omitempty,gte=30,lte=250"`
+	LookingFor       string    `json:"lookingFor" validate:"max=64"`
+	TelegramID       uint64    `json:"telegramId" validate:"required"`
 	TelegramUserName string    `json:"telegramUserName"`
 	Firstname        string    `json:"firstName"`
 	Lastname         string    `json:"lastName"`
 	LanguageCode     string    `json:"languageCode"`
-	AllowsWriteToPm  string    `json:"allowsWriteToPm"`
+	AllowsWriteToPm  bool      `json:"allowsWriteToPm"`
 	QueryID          string    `json:"queryId"`
-	Latitude         string    `json:"latitude"`
-	Longitude        string    `json:"longitude"`
-	AgeFrom          string    `json:"ageFrom"`
-	AgeTo            string    `json:"ageTo"`
-	Distance         string    `json:"distance"`
-	Page             string    `json:"page"`
-	Size             string    `json:"size"`
+	Latitude         string    `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude        string    `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	AgeFrom          uint8     `json:"ageFrom" validate:"omitempty,gte=18,lte=100"`
+	AgeTo            uint8     `json:"ageTo" validate:"omitempty,gte=18,lte=100,gtefield=AgeFrom"`
+	Distance         uint64    `json:"distance" validate:"omitempty,gte=1000,lte=20000000"`
+	Page             uint64    `json:"page"`
+	Size             uint64    `json:"size"`
 	Image            []byte    `json:"image"`
+	IsInvisible      string    `json:"isInvisible" validate:"omitempty,boolean"`
 }
 
 type RequestDeleteProfile struct {
-	ID string `json:"id"`
+	SessionID string `json:"sessionId" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+}
+
+type RequestRestoreProfile struct {
+	SessionID string `json:"sessionId" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+}
+
+// DeletionGracePeriod is the time during which the deleted profile can be restored. The personal data of the
+// profile is erased after that.
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+// DeletedProfile is the profile which waits for its personal data to be erased
+type DeletedProfile struct {
+	ID        uint64    `json:"id"`
+	SessionID string    `json:"sessionId"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// DeletionAuditProfile records what has been erased for the profile, it keeps no personal data
+type DeletionAuditProfile struct {
+	ID                uint64           `json:"id"`
+	ProfileID         uint64           `json:"profileId"`
+	DeletedAt         time.Time        `json:"deletedAt"`
+	ErasedAt          time.Time        `json:"erasedAt"`
+	RemovedRows       map[string]int64 `json:"removedRows"`
+	RemovedFiles      int              `json:"removedFiles"`
+	IsIdentityRemoved bool             `json:"isIdentityRemoved"`
+	CreatedAt         time.Time        `json:"createdAt"`
 }
 
 type RequestDeleteProfileImage struct {
@@ -98,7 +140,8 @@ type RequestDeleteProfileImage struct {
 type ContentListProfile struct {
 	ID         uint64                    `json:"id"`
 	IsOnline   bool                      `json:"isOnline"`
-	LastOnline time.Time                 `json:"lastOnline"`
+	IsVerified bool                      `json:"isVerified"`
+	LastOnline *time.Time                `json:"lastOnline"`
 	Image      *ResponseImageProfile     `json:"image"`
 	Navigator  *ResponseNavigatorProfile `json:"navigator"`
 }
@@ -134,9 +177,10 @@ type ResponseProfileDetail struct {
 	IsShowDistance bool                      `json:"isShowDistance"`
 	IsInvisible    bool                      `json:"isInvisible"`
 	IsOnline       bool                      `json:"isOnline"`
+	IsVerified     bool                      `json:"isVerified"`
 	CreatedAt      time.Time                 `json:"createdAt"`
 	UpdatedAt      time.Time                 `json:"updatedAt"`
-	LastOnline     time.Time                 `json:"lastOnline"`
+	LastOnline     *time.Time                `json:"lastOnline"`
 	Images         []*ImageProfile           `json:"images"`
 	Telegram       *TelegramProfile          `json:"telegram"`
 	Navigator      *ResponseNavigatorProfile `json:"navigator"`
@@ -146,14 +190,32 @@ type ResponseProfileDetail struct {
 
 type QueryParamsProfileList struct {
 	pagination.Pagination
-	SessionID    string `json:"sessionId"`
-	AgeFrom      string `json:"ageFrom"`
-	AgeTo        string `json:"ageTo"`
-	SearchGender string `json:"searchGender"`
-	LookingFor   string `json:"lookingFor"`
-	Distance     string `json:"distance"`
-	Latitude     string `json:"latitude"`
-	Longitude    string `json:"longitude"`
+	SessionID            string `json:"sessionId"`
+	AgeFrom              string `json:"ageFrom"`
+	AgeTo                string `json:"ageTo"`
+	SearchGender         string `json:"searchGender"`
+	LookingFor           string `json:"lookingFor"`
+	Distance             string `json:"distance"`
+	Latitude             string `json:"latitude"`
+	Longitude            string `json:"longitude"`
+	IsReciprocalDisabled bool   `json:"isReciprocalDisabled"`
+	IsVerified           bool   `json:"isVerified"`
+}
+
+type CandidateProfile struct {
+	Profile            *Profile `json:"profile"`
+	Distance           float64  `json:"distance"`
+	CountImages        uint64   `json:"countImages"`
+	CountLikesReceived uint64   `json:"countLikesReceived"`
+	CountLikesReturned uint64   `json:"countLikesReturned"`
+	IsLikedViewer      bool     `json:"isLikedViewer"`
+	Score              float64  `json:"score"`
+}
+
+type RankViewer struct {
+	Profile   *Profile          `json:"profile"`
+	LikeStats *LikeStatsProfile `json:"likeStats"`
+	RankedAt  time.Time         `json:"rankedAt"`
 }
 
 type QueryParamsGetProfileByTelegramID struct {
@@ -194,10 +256,14 @@ type Point struct {
 	Longitude float64 `json:"longitude"`
 }
 
+// NavigatorProfile is the location of the profile, the moves are tracked to refuse the location jumps
 type NavigatorProfile struct {
-	ID        uint64 `json:"id"`
-	ProfileID uint64 `json:"profileId"`
-	Location  *Point `json:"location"`
+	ID             uint64     `json:"id"`
+	ProfileID      uint64     `json:"profileId"`
+	Location       *Point     `json:"location"`
+	MovedAt        *time.Time `json:"-"`
+	JumpCount      uint64     `json:"-"`
+	JumpsStartedAt *time.Time `json:"-"`
 }
 
 type FilterProfile struct {
@@ -245,17 +311,65 @@ type ResponseFilterProfile struct {
 	Size         uint64 `json:"size"`
 }
 
+// DistanceBuckets are the upper bounds and the steps in metres the distance which is used by the feed and shown to
+// the viewers is rounded up to
+var DistanceBuckets = []struct {
+	UpperBound float64
+	Step       float64
+}{
+	{UpperBound: 1000, Step: 1000},
+	{UpperBound: 10000, Step: 1000},
+	{UpperBound: 50000, Step: 5000},
+	{UpperBound: math.Inf(1), Step: 10000},
+}
+
+// BandDistance rounds the distance in metres up to the step of its bucket
+func BandDistance(distance float64) float64 {
+	for _, b := range DistanceBuckets {
+		if distance <= b.UpperBound {
+			return math.Max(math.Ceil(distance/b.Step)*b.Step, b.Step)
+		}
+	}
+	return distance
+}
+
+// DistanceNoiseRatio is the maximum share of the distance which is added or subtracted as noise before the distance
+// is banded for the viewer
+const DistanceNoiseRatio = 0.15
+
+// GetFeedRadius returns the radius of the feed in metres rounded up to its distance band, the radius is requested in
+// kilometres
+func GetFeedRadius(distance string) (float64, error) {
+	kilometres, err := strconv.ParseFloat(distance, 64)
+	if err != nil {
+		return 0, err
+	}
+	return BandDistance(kilometres * 1000), nil
+}
+
 type ResponseNavigatorProfile struct {
-	Distance float64 `json:"distance"`
+	Distance *float64 `json:"distance"`
 }
 
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusPublished = "published"
+	ReviewStatusRejected  = "rejected"
+)
+
+// ReviewReportLimit is the number of reports after which a published review is held for moderation
+const ReviewReportLimit = 3
+
+// ReviewProfile is the review of the profile HumanID written by the profile ProfileID
 type ReviewProfile struct {
 	ID         uint64    `json:"id"`
 	ProfileID  uint64    `json:"profileId"`
+	HumanID    uint64    `json:"humanId"`
 	Message    string    `json:"message"`
 	Rating     float32   `json:"rating"`
 	HasDeleted bool      `json:"hasDeleted"`
 	HasEdited  bool      `json:"hasEdited"`
+	Status     string    `json:"status"`
 	CreatedAt  time.Time `json:"createdAt"`
 	UpdatedAt  time.Time `json:"updatedAt"`
 }
@@ -263,27 +377,37 @@ type ReviewProfile struct {
 type ResponseReviewProfile struct {
 	ID         uint64    `json:"id"`
 	ProfileID  uint64    `json:"profileId"`
+	HumanID    uint64    `json:"humanId"`
 	Message    string    `json:"message"`
 	Rating     float32   `json:"rating"`
 	HasDeleted bool      `json:"hasDeleted"`
 	HasEdited  bool      `json:"hasEdited"`
+	Status     string    `json:"status"`
 	CreatedAt  time.Time `json:"createdAt"`
 	UpdatedAt  time.Time `json:"updatedAt"`
 	SessionID  string    `json:"sessionId"`
 }
 
+// QueryParamsReviewDetail has the session of the caller, the review which is not published is shown to its author only
+type QueryParamsReviewDetail struct {
+	SessionID string `json:"sessionId"`
+}
+
 type QueryParamsReviewList struct {
 	pagination.Pagination
 	ProfileID string `json:"profileId"`
+	HumanID   string `json:"humanId"`
 }
 
 type ContentReviewProfile struct {
 	ID          uint64    `json:"id"`
 	ProfileID   uint64    `json:"profileId"`
+	HumanID     uint64    `json:"humanId"`
 	Message     string    `json:"message"`
 	Rating      float32   `json:"rating"`
 	HasDeleted  bool      `json:"hasDeleted"`
 	HasEdited   bool      `json:"hasEdited"`
+	Status      string    `json:"status"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 	DisplayName string    `json:"displayName"`
@@ -294,11 +418,13 @@ type ResponseListReview struct {
 	*pagination.Pagination
 	RatingAverage            float32                 `json:"ratingAverage"`
 	CountItemsTodayByProfile uint                    `json:"countItemsTodayByProfile"`
+	ProfileReviewID          *uint64                 `json:"profileReviewId"`
 	Content                  []*ContentReviewProfile `json:"content"`
 }
 
 type RequestAddReview struct {
 	ProfileID string `json:"profileId"`
+	HumanID   string `json:"humanId"`
 	Message   string `json:"message"`
 	Rating    string `json:"rating"`
 }
@@ -314,6 +440,36 @@ type RequestDeleteReview struct {
 	ID string `json:"id"`
 }
 
+// ReviewReportProfile is the report of the review ReviewID made by the profile ProfileID
+type ReviewReportProfile struct {
+	ID        uint64    `json:"id"`
+	ReviewID  uint64    `json:"reviewId"`
+	ProfileID uint64    `json:"profileId"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RequestAddReviewReport struct {
+	SessionID string `json:"sessionId"`
+	ReviewID  string `json:"reviewId"`
+	Reason    string `json:"reason"`
+}
+
+type RequestModerateReview struct {
+	ID string `json:"id"`
+}
+
+type QueryParamsReviewModerationList struct {
+	pagination.Pagination
+	Status string `json:"status"`
+}
+
+type ResponseListReviewModeration struct {
+	*pagination.Pagination
+	Content []*ContentReviewProfile `json:"content"`
+}
+
 type LikeProfile struct {
 	ID        uint64    `json:"id"`
 	ProfileID uint64    `json:"profileId"`
@@ -323,6 +479,45 @@ type LikeProfile struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+const (
+	LikeDirectionIncoming = "incoming"
+	LikeDirectionOutgoing = "outgoing"
+	LikeDirectionAll      = "all"
+)
+
+type QueryParamsLikeList struct {
+	pagination.Pagination
+	SessionID string `json:"sessionId"`
+	Direction string `json:"-"`
+}
+
+type ResponseLikeHumanProfile struct {
+	ID          uint64                `json:"id"`
+	DisplayName string                `json:"displayName"`
+	Image       *ResponseImageProfile `json:"image"`
+}
+
+type ContentLikeProfile struct {
+	ID        uint64                    `json:"id"`
+	Direction string                    `json:"direction"`
+	IsLiked   bool                      `json:"isLiked"`
+	IsHidden  bool                      `json:"isHidden"`
+	CreatedAt time.Time                 `json:"createdAt"`
+	UpdatedAt time.Time                 `json:"updatedAt"`
+	Human     *ResponseLikeHumanProfile `json:"human"`
+}
+
+type ResponseListLike struct {
+	*pagination.Pagination
+	Content []*ContentLikeProfile `json:"content"`
+}
+
+type LikeStatsProfile struct {
+	ProfileID  uint64  `json:"profileId"`
+	CountLiked uint64  `json:"countLiked"`
+	AgeAverage float64 `json:"ageAverage"`
+}
+
 type RequestAddLike struct {
 	SessionID string `json:"sessionId"`
 	HumanID   string `json:"humanId"`
@@ -342,6 +537,192 @@ type ResponseLikeProfile struct {
 	UpdatedAt *time.Time `json:"updatedAt"`
 }
 
+const (
+	SwipeActionLike      = "like"
+	SwipeActionPass      = "pass"
+	SwipeActionSuperLike = "superLike"
+)
+
+const (
+	// SwipePassTTL is the time after which the passed profile returns to the feed
+	SwipePassTTL = 30 * 24 * time.Hour
+	// SwipeRewindLimit is the number of rewinds per day, rewind is available only with FeatureRewind
+	SwipeRewindLimit = 10
+)
+
+type SwipeProfile struct {
+	ID        uint64     `json:"id"`
+	ProfileID uint64     `json:"profileId"`
+	HumanID   uint64     `json:"humanId"`
+	Action    string     `json:"action"`
+	IsRewound bool       `json:"isRewound"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type RequestAddSwipe struct {
+	SessionID string `json:"sessionId"`
+	HumanID   string `json:"humanId"`
+	Action    string `json:"action"`
+}
+
+type RequestRewindSwipe struct {
+	SessionID string `json:"sessionId"`
+}
+
+const (
+	FeatureInvisible      = "invisible"
+	FeatureUnlimitedLikes = "unlimitedLikes"
+	FeatureWhoLikedMe     = "whoLikedMe"
+	FeatureRewind         = "rewind"
+	// FeaturePremiumLimits raises the daily quotas to their premium limits
+	FeaturePremiumLimits = "premiumLimits"
+)
+
+const (
+	SubscriptionPlanPremium = "premium"
+)
+
+const (
+	SubscriptionPeriodMonth   = "month"
+	SubscriptionPeriodQuarter = "quarter"
+	SubscriptionPeriodYear    = "year"
+)
+
+const (
+	SubscriptionStatusActive  = "active"
+	SubscriptionStatusExpired = "expired"
+)
+
+type SubscriptionProfile struct {
+	ID        uint64    `json:"id"`
+	ProfileID uint64    `json:"profileId"`
+	Plan      string    `json:"plan"`
+	Period    string    `json:"period"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RequestAddSubscription struct {
+	ProfileID string `json:"profileId"`
+	Plan      string `json:"plan"`
+	Period    string `json:"period"`
+}
+
+const (
+	// PaymentCurrencyStars is the Telegram Stars currency code
+	PaymentCurrencyStars = "XTR"
+)
+
+const (
+	PaymentStatusPending = "pending"
+	PaymentStatusPaid    = "paid"
+)
+
+// SubscriptionPrices are the prices of the premium periods in Telegram Stars
+var SubscriptionPrices = map[string]int{
+	SubscriptionPeriodMonth:   250,
+	SubscriptionPeriodQuarter: 650,
+	SubscriptionPeriodYear:    2000,
+}
+
+// SubscriptionDescriptions are the invoice descriptions of the premium periods
+var SubscriptionDescriptions = map[string]string{
+	SubscriptionPeriodMonth:   "Премиум на 1 месяц",
+	SubscriptionPeriodQuarter: "Премиум на 3 месяца",
+	SubscriptionPeriodYear:    "Премиум на 12 месяцев",
+}
+
+type PaymentProfile struct {
+	ID                      uint64    `json:"id"`
+	ProfileID               uint64    `json:"profileId"`
+	Plan                    string    `json:"plan"`
+	Period                  string    `json:"period"`
+	Amount                  int       `json:"amount"`
+	Currency                string    `json:"currency"`
+	Status                  string    `json:"status"`
+	Payload                 string    `json:"payload"`
+	TelegramPaymentChargeID string    `json:"telegramPaymentChargeId"`
+	ProviderPaymentChargeID string    `json:"providerPaymentChargeId"`
+	CreatedAt               time.Time `json:"createdAt"`
+	UpdatedAt               time.Time `json:"updatedAt"`
+}
+
+type RequestAddPayment struct {
+	SessionID string `json:"sessionId"`
+	Plan      string `json:"plan"`
+	Period    string `json:"period"`
+}
+
+type ResponseAddPayment struct {
+	ID          uint64 `json:"id"`
+	InvoiceLink string `json:"invoiceLink"`
+}
+
+// CheckoutPayment is the payment data received from Telegram in pre_checkout_query and successful_payment
+type CheckoutPayment struct {
+	Payload                 string `json:"payload"`
+	Currency                string `json:"currency"`
+	TotalAmount             int    `json:"totalAmount"`
+	TelegramPaymentChargeID string `json:"telegramPaymentChargeId"`
+	ProviderPaymentChargeID string `json:"providerPaymentChargeId"`
+}
+
+const (
+	QuotaActionLike      = "like"
+	QuotaActionComplaint = "complaint"
+	QuotaActionReview    = "review"
+)
+
+// QuotaProfile is the daily quota of the action for the profile, the quota is reset at ResetAt
+type QuotaProfile struct {
+	Action      string    `json:"action"`
+	Limit       uint64    `json:"limit"`
+	Used        uint64    `json:"used"`
+	Remaining   uint64    `json:"remaining"`
+	IsUnlimited bool      `json:"isUnlimited"`
+	ResetAt     time.Time `json:"resetAt"`
+}
+
+const (
+	// VisitRetention is the time after which the visits are deleted
+	VisitRetention = 90 * 24 * time.Hour
+)
+
+type VisitProfile struct {
+	ID        uint64    `json:"id"`
+	ProfileID uint64    `json:"profileId"`
+	ViewerID  uint64    `json:"viewerId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type QueryParamsVisitList struct {
+	pagination.Pagination
+	SessionID string `json:"sessionId"`
+}
+
+type ResponseVisitViewerProfile struct {
+	ID          uint64                `json:"id"`
+	DisplayName string                `json:"displayName"`
+	Image       *ResponseImageProfile `json:"image"`
+}
+
+type ContentVisitProfile struct {
+	ID        uint64                      `json:"id"`
+	VisitedAt time.Time                   `json:"visitedAt"`
+	Viewer    *ResponseVisitViewerProfile `json:"viewer"`
+}
+
+type ResponseListVisit struct {
+	*pagination.Pagination
+	Content []*ContentVisitProfile `json:"content"`
+}
+
 type BlockedProfile struct {
 	ID            uint64    `json:"id"`
 	ProfileID     uint64    `json:"profileId"`
@@ -357,7 +738,35 @@ type RequestAddBlock struct {
 }
 
 type RequestUpdateBlock struct {
-	ID string `json:"id"`
+	SessionID string `json:"sessionId" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+}
+
+type RequestDeleteBlock struct {
+	SessionID     string `json:"sessionId"`
+	BlockedUserID string `json:"blockedUserId"`
+}
+
+type QueryParamsBlockList struct {
+	pagination.Pagination
+	SessionID string `json:"sessionId"`
+}
+
+type ResponseBlockedUserProfile struct {
+	ID          uint64                `json:"id"`
+	DisplayName string                `json:"displayName"`
+	Image       *ResponseImageProfile `json:"image"`
+}
+
+type ContentBlockProfile struct {
+	ID          uint64                      `json:"id"`
+	BlockedAt   time.Time                   `json:"blockedAt"`
+	BlockedUser *ResponseBlockedUserProfile `json:"blockedUser"`
+}
+
+type ResponseListBlock struct {
+	*pagination.Pagination
+	Content []*ContentBlockProfile `json:"content"`
 }
 
 type ComplaintProfile struct {
@@ -374,3 +783,240 @@ type RequestAddComplaint struct {
 	ComplaintUserID string `json:"complaintUserId"`
 	Reason          string `json:"reason"`
 }
+
+// ComplaintReasonUnderage is the reason of the complaint about the profile owner who seems to be under MinAge
+const ComplaintReasonUnderage = "underage"
+
+// UnderageComplaintLimit is the number of the distinct profiles complaining about the underage owner after which
+// the age check is requested and the profile is hidden from the feed
+const UnderageComplaintLimit = 3
+
+const (
+	AgeVerificationStatusPending   = "pending"
+	AgeVerificationStatusConfirmed = "confirmed"
+	AgeVerificationStatusRejected  = "rejected"
+)
+
+const (
+	AgeVerificationReasonComplaint      = "complaint"
+	AgeVerificationReasonBirthdayChange = "birthday_change"
+)
+
+// AgeVerificationProfile is the check of the age of the suspected minor. The profile is hidden from the feed
+// while the check is pending, the rejected profile is blocked.
+type AgeVerificationProfile struct {
+	ID        uint64    `json:"id"`
+	ProfileID uint64    `json:"profileId"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ContentAgeVerificationProfile struct {
+	ID          uint64    `json:"id"`
+	ProfileID   uint64    `json:"profileId"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	DisplayName string    `json:"displayName"`
+	Birthday    time.Time `json:"birthday"`
+	SessionID   string    `json:"sessionId"`
+}
+
+type RequestModerateAgeVerification struct {
+	ID uint64 `json:"id" validate:"required"`
+}
+
+type QueryParamsAgeVerificationList struct {
+	pagination.Pagination
+	Status string `json:"status"`
+}
+
+type ResponseListAgeVerification struct {
+	*pagination.Pagination
+	Content []*ContentAgeVerificationProfile `json:"content"`
+}
+
+const (
+	SelfieStatusChallenged = "challenged"
+	SelfieStatusPending    = "pending"
+	SelfieStatusApproved   = "approved"
+	SelfieStatusRejected   = "rejected"
+)
+
+// SelfieChallengeTTL is the time during which the selfie in the issued pose has to be uploaded
+const SelfieChallengeTTL = 10 * time.Minute
+
+// SelfiePoses are the poses one of which is issued for the selfie, so a photo taken earlier can't be uploaded
+var SelfiePoses = []string{
+	"thumb_up", "peace_sign", "hand_on_head", "touch_nose", "wave_hand", "look_left", "look_right", "three_fingers",
+}
+
+// SelfieVerificationProfile is the check of the profile by the selfie in the issued pose. The challenged check
+// waits for the selfie, the pending one waits for a moderator. The profile with the approved check is verified.
+type SelfieVerificationProfile struct {
+	ID        uint64    `json:"id"`
+	ProfileID uint64    `json:"profileId"`
+	Pose      string    `json:"pose"`
+	Status    string    `json:"status"`
+	FilePath  string    `json:"-"`
+	Score     *float64  `json:"score"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ContentSelfieVerificationProfile struct {
+	ID          uint64    `json:"id"`
+	ProfileID   uint64    `json:"profileId"`
+	Pose        string    `json:"pose"`
+	Status      string    `json:"status"`
+	Score       *float64  `json:"score"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	DisplayName string    `json:"displayName"`
+	SessionID   string    `json:"sessionId"`
+}
+
+type RequestAddSelfieChallenge struct {
+	SessionID string `json:"sessionId" validate:"required"`
+}
+
+type RequestAddSelfie struct {
+	SessionID string `json:"sessionId" validate:"required"`
+	ID        uint64 `json:"id" validate:"required"`
+}
+
+type RequestModerateSelfie struct {
+	ID uint64 `json:"id" validate:"required"`
+}
+
+type QueryParamsSelfieModerationList struct {
+	pagination.Pagination
+	Status string `json:"status"`
+}
+
+type ResponseListSelfieModeration struct {
+	*pagination.Pagination
+	Content []*ContentSelfieVerificationProfile `json:"content"`
+}
+
+const (
+	ExportStatusPending = "pending"
+	ExportStatusReady   = "ready"
+	ExportStatusFailed  = "failed"
+	ExportStatusExpired = "expired"
+)
+
+// ExportRetention is the time during which the ready export can be downloaded
+const ExportRetention = 7 * 24 * time.Hour
+
+// ExportProfile is the archive with the personal data of the profile, it is downloaded with the token
+type ExportProfile struct {
+	ID        uint64     `json:"id"`
+	ProfileID uint64     `json:"profileId"`
+	Status    string     `json:"status"`
+	Token     string     `json:"-"`
+	FilePath  string     `json:"-"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type RequestAddExport struct {
+	SessionID string `json:"sessionId"`
+}
+
+type QueryParamsExportDetail struct {
+	SessionID string `json:"sessionId" validate:"required"`
+}
+
+// ResponseExportProfile is the export with the download url, the url is set when the archive is ready
+type ResponseExportProfile struct {
+	*ExportProfile
+	DownloadURL *string `json:"downloadUrl"`
+}
+
+// GetExportDownloadURL returns the url the archive of the export is downloaded by
+func GetExportDownloadURL(downloadURL string, ex *ExportProfile) string {
+	return fmt.Sprintf("%s/%d?token=%s", downloadURL, ex.ID, url.QueryEscape(ex.Token))
+}
+
+type QueryParamsExportDownload struct {
+	Token string `json:"token"`
+}
+
+// ExportDataProfile is the personal data of the profile which is put into the export as JSON
+type ExportDataProfile struct {
+	Profile       *Profile                `json:"profile"`
+	Telegram      *TelegramProfile        `json:"telegram"`
+	Navigator     *ExportNavigatorProfile `json:"navigator"`
+	Filter        *FilterProfile          `json:"filter"`
+	Images        []*ImageProfile         `json:"images"`
+	LikesSent     []*LikeProfile          `json:"likesSent"`
+	LikesReceived []*LikeProfile          `json:"likesReceived"`
+	Blocks        []*BlockedProfile       `json:"blocks"`
+	Complaints    []*ComplaintProfile     `json:"complaints"`
+	Reviews       []*ReviewProfile        `json:"reviews"`
+	ExportedAt    time.Time               `json:"exportedAt"`
+}
+
+// ExportNavigatorProfile is the navigator with the moves which are kept to refuse the location jumps. Only the last
+// location is stored, the previous locations are overwritten on every move.
+type ExportNavigatorProfile struct {
+	ID             uint64     `json:"id"`
+	ProfileID      uint64     `json:"profileId"`
+	Location       *Point     `json:"location"`
+	MovedAt        *time.Time `json:"movedAt"`
+	JumpCount      uint64     `json:"jumpCount"`
+	JumpsStartedAt *time.Time `json:"jumpsStartedAt"`
+}
+
+// NewExportNavigator returns nil when the profile has no navigator
+func NewExportNavigator(n *NavigatorProfile) *ExportNavigatorProfile {
+	if n == nil {
+		return nil
+	}
+	return &ExportNavigatorProfile{
+		ID:             n.ID,
+		ProfileID:      n.ProfileID,
+		Location:       n.Location,
+		MovedAt:        n.MovedAt,
+		JumpCount:      n.JumpCount,
+		JumpsStartedAt: n.JumpsStartedAt,
+	}
+}
+
+// GetAge returns the number of full years since the birthday at the moment
+func GetAge(birthday time.Time, moment time.Time) int {
+	age := moment.Year() - birthday.Year()
+	if moment.Month() < birthday.Month() || (moment.Month() == birthday.Month() && moment.Day() < birthday.Day()) {
+		age--
+	}
+	return age
+}
+
+// IsAdult reports whether the owner with the birthday is at least MinAge years old at the moment
+func IsAdult(birthday time.Time, moment time.Time) bool {
+	return !birthday.AddDate(MinAge, 0, 0).After(moment)
+}
+
+// IsSameDate reports whether the dates of a and b are the same, the time of the day is ignored
+func IsSameDate(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+// GetSubscriptionEndedAt returns the end of the subscription period which starts at startedAt
+func GetSubscriptionEndedAt(startedAt time.Time, period string) (time.Time, error) {
+	switch period {
+	case SubscriptionPeriodMonth:
+		return startedAt.AddDate(0, 1, 0), nil
+	case SubscriptionPeriodQuarter:
+		return startedAt.AddDate(0, 3, 0), nil
+	case SubscriptionPeriodYear:
+		return startedAt.AddDate(1, 0, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown subscription period: %s", period)
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"encoding/json"
+	"github.com/EvgeniyBudaev/love-server/internal/config"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const invoiceTitle = "Премиум"
+
+type Telegram struct {
+	bot    *tgbotapi.BotAPI
+	logger logger.Logger
+}
+
+func NewTelegram(config *config.Config, l logger.Logger) (*Telegram, error) {
+	apiEndpoint := config.TelegramAPIEndpoint
+	if apiEndpoint == "" {
+		apiEndpoint = tgbotapi.APIEndpoint
+	}
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(config.TelegramBotToken, apiEndpoint)
+	if err != nil {
+		l.Error("error func NewTelegram, method NewBotAPIWithAPIEndpoint", zap.Error(err))
+		return nil, errors.Wrap(err, "unable to create the telegram bot")
+	}
+	return &Telegram{bot: bot, logger: l}, nil
+}
+
+// CreateInvoiceLink creates a link to the invoice in Telegram Stars, the link is opened by the mini app
+func (t *Telegram) CreateInvoiceLink(p *profile.PaymentProfile) (string, error) {
+	params := make(tgbotapi.Params)
+	params["title"] = invoiceTitle
+	params["description"] = profile.SubscriptionDescriptions[p.Period]
+	params["payload"] = p.Payload
+	params["currency"] = p.Currency
+	prices := []tgbotapi.LabeledPrice{{Label: invoiceTitle, Amount: p.Amount}}
+	if err := params.AddInterface("prices", prices); err != nil {
+		t.logger.Error("error func CreateInvoiceLink, method AddInterface", zap.Error(err))
+		return "", err
+	}
+	response, err := t.bot.MakeRequest("createInvoiceLink", params)
+	if err != nil {
+		t.logger.Error("error func CreateInvoiceLink, method MakeRequest", zap.Error(err))
+		return "", errors.Wrap(err, "unable to create the invoice link")
+	}
+	var link string
+	if err := json.Unmarshal(response.Result, &link); err != nil {
+		t.logger.Error("error func CreateInvoiceLink, method Unmarshal", zap.Error(err))
+		return "", err
+	}
+	return link, nil
+}
+
+// AnswerPreCheckoutQuery confirms the checkout, a non-empty errorMessage rejects it
+func (t *Telegram) AnswerPreCheckoutQuery(queryID string, errorMessage string) error {
+	config := tgbotapi.PreCheckoutConfig{
+		PreCheckoutQueryID: queryID,
+		OK:                 errorMessage == "",
+		ErrorMessage:       errorMessage,
+	}
+	if _, err := t.bot.Request(config); err != nil {
+		t.logger.Error("error func AnswerPreCheckoutQuery, method Request", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (t *Telegram) SendMessage(chatID int64, text string) error {
+	if _, err := t.bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		t.logger.Error("error func SendMessage, method Send", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (t *Telegram) GetUpdatesChan() tgbotapi.UpdatesChannel {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+	return t.bot.GetUpdatesChan(u)
+}
+
+func (t *Telegram) StopReceivingUpdates() {
+	t.bot.StopReceivingUpdates()
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/entity/health"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	healthUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/health"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+type HandlerHealth struct {
+	logger logger.Logger
+	uc     *healthUseCase.UseCaseHealth
+}
+
+func NewHandlerHealth(l logger.Logger, uc *healthUseCase.UseCaseHealth) *HandlerHealth {
+	return &HandlerHealth{logger: l, uc: uc}
+}
+
+// GetLivenessHandler reports that the process is up, it does not check the dependencies
+func (h *HandlerHealth) GetLivenessHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		return ctf.Status(http.StatusOK).JSON(health.ResponseLiveness{Status: health.StatusUp})
+	}
+}
+
+// GetReadinessHandler returns the report of the checks, the status is 503 when any check is down
+func (h *HandlerHealth) GetReadinessHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		response := h.uc.CheckReadiness(ctf.UserContext())
+		if response.Status != health.StatusUp {
+			h.logger.With(ctf.UserContext()).Warn("GET /readyz is not ready")
+			return ctf.Status(http.StatusServiceUnavailable).JSON(response)
+		}
+		return ctf.Status(http.StatusOK).JSON(response)
+	}
+}
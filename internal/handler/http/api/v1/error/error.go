@@ -1,11 +1,16 @@
 package error
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+)
 
 type ResponseError struct {
-	Message    string `json:"message"`
-	Success    bool   `json:"success"`
-	StatusCode int    `json:"statusCode"`
+	Message    string                 `json:"message"`
+	Success    bool                   `json:"success"`
+	StatusCode int                    `json:"statusCode"`
+	Code       string                 `json:"code,omitempty"`
+	Fields     []*appError.FieldError `json:"fields,omitempty"`
 }
 
 type CustomError struct {
@@ -2,30 +2,71 @@ package response
 
 import (
 	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
 	errorDomain "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/error"
 	"github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/success"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
+	"strings"
 )
 
+// statusByKind is the response status of the domain error kind
+var statusByKind = map[appError.Kind]int{
+	appError.KindNotFound:   http.StatusNotFound,
+	appError.KindConflict:   http.StatusConflict,
+	appError.KindForbidden:  http.StatusForbidden,
+	appError.KindValidation: http.StatusBadRequest,
+	appError.KindInternal:   http.StatusInternalServerError,
+}
+
+// WrapError writes the error response. The status and the code of the domain error are derived from its kind,
+// httpStatusCode is used for the other errors. The message of the internal error is not returned.
 func WrapError(ctf *fiber.Ctx, err error, httpStatusCode int) error {
+	if domainError, ok := appError.As(err); ok {
+		status, ok := statusByKind[domainError.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		msg := errorDomain.ResponseError{
+			StatusCode: status,
+			Success:    false,
+			Message:    domainError.Message,
+			Code:       string(domainError.Kind),
+			Fields:     domainError.Fields,
+		}
+		return ctf.Status(status).JSON(msg)
+	}
 	var customError *errorDomain.CustomError
 	if errors.As(err, &customError) {
+		message := http.StatusText(customError.StatusCode)
+		if customError.Err != nil {
+			message = customError.Err.Error()
+		}
 		msg := errorDomain.ResponseError{
 			StatusCode: customError.StatusCode,
 			Success:    false,
-			Message:    customError.Err.Error(),
+			Message:    message,
+			Code:       codeByStatus(customError.StatusCode),
 		}
 		return ctf.Status(customError.StatusCode).JSON(msg)
 	}
+	if httpStatusCode >= http.StatusInternalServerError {
+		return WrapError(ctf, appError.NewInternal(err), httpStatusCode)
+	}
 	msg := errorDomain.ResponseError{
 		StatusCode: httpStatusCode,
 		Success:    false,
 		Message:    err.Error(),
+		Code:       codeByStatus(httpStatusCode),
 	}
 	return ctf.Status(httpStatusCode).JSON(msg)
 }
 
+// codeByStatus returns the code of the error which has no kind, e.g. TOO_MANY_REQUESTS for 429
+func codeByStatus(status int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
 func WrapOk(ctf *fiber.Ctx, data interface{}) error {
 	msg := success.Success{
 		Data:       data,
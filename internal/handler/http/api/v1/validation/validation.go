@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// tagAdult is the rule of the birthday of the profile owner, see profile.MinAge
+const tagAdult = "adult"
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// The fields are reported by their json names, the same names are used by the form values
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	if err := v.RegisterValidation(tagAdult, isAdult); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Validate checks the request by its validate tags. The failed rules are returned as the validation error with an
+// error for every field.
+func Validate(request interface{}) error {
+	err := validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return appError.NewInternal(err)
+	}
+	fields := make([]*appError.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, &appError.FieldError{Field: fe.Field(), Message: message(fe)})
+	}
+	return appError.NewValidation("invalid request", fields...)
+}
+
+func isAdult(fl validator.FieldLevel) bool {
+	birthday, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	return profile.IsAdult(birthday, time.Now().UTC())
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return fmt.Sprintf("is required with %s", jsonName(fe.Param()))
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "lte":
+		if fe.Kind() == reflect.Struct {
+			return "must not be in the future"
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gtefield":
+		return fmt.Sprintf("must not be less than %s", jsonName(fe.Param()))
+	case tagAdult:
+		return fmt.Sprintf("the age must be at least %d", profile.MinAge)
+	case "latitude":
+		return "must be a latitude between -90 and 90"
+	case "longitude":
+		return "must be a longitude between -180 and 180"
+	case "boolean":
+		return "must be true or false"
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
+
+// jsonName returns the json name of the field which is referenced by the cross-field rule, e.g. ageFrom for AgeFrom
+func jsonName(field string) string {
+	if field == "" {
+		return field
+	}
+	return strings.ToLower(field[:1]) + field[1:]
+}
@@ -0,0 +1,24 @@
+package openapi
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/openapi"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+type HandlerOpenAPI struct {
+	logger   logger.Logger
+	document *openapi.Document
+}
+
+func NewHandlerOpenAPI(l logger.Logger, d *openapi.Document) *HandlerOpenAPI {
+	return &HandlerOpenAPI{logger: l, document: d}
+}
+
+// GetDocumentHandler returns the OpenAPI document of the api as it is, without success.Success
+func (h *HandlerOpenAPI) GetDocumentHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		return ctf.Status(http.StatusOK).JSON(h.document)
+	}
+}
@@ -1,11 +1,16 @@
 package profile
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
 	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
 	errorDomain "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/error"
 	r "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/response"
+	"github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/validation"
 	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
 	profileUseCase "github.com/EvgeniyBudaev/love-server/internal/useCase/profile"
 	"github.com/gofiber/fiber/v2"
 	"github.com/kolesa-team/go-webp/encoder"
@@ -23,36 +28,62 @@ import (
 )
 
 type HandlerProfile struct {
-	logger logger.Logger
-	uc     *profileUseCase.UseCaseProfile
+	logger  logger.Logger
+	uc      *profileUseCase.UseCaseProfile
+	metrics *metrics.Metrics
+	// selfieDirectory keeps the selfies of the verification checks, they are shown to the moderators only
+	selfieDirectory string
+	// exportDownloadURL is the url the export archives are downloaded by, exportDownloadURL/id?token=...
+	exportDownloadURL string
 }
 
-func NewHandlerProfile(l logger.Logger, uc *profileUseCase.UseCaseProfile) *HandlerProfile {
-	return &HandlerProfile{logger: l, uc: uc}
+func NewHandlerProfile(l logger.Logger, uc *profileUseCase.UseCaseProfile, m *metrics.Metrics, selfieDirectory string,
+	exportDownloadURL string) *HandlerProfile {
+	return &HandlerProfile{
+		logger:            l,
+		uc:                uc,
+		metrics:           m,
+		selfieDirectory:   selfieDirectory,
+		exportDownloadURL: exportDownloadURL,
+	}
+}
+
+// begin starts the span of the handler as a child of the request span and puts it to the user context, which
+// is passed to the use case, and sets the route of the request logger. The returned func ends the span, it is meant
+// to be deferred.
+func (h *HandlerProfile) begin(ctf *fiber.Ctx, name string) func() {
+	ctx, span := tracing.Start(ctf.UserContext(), "HandlerProfile."+name)
+	logger.SetRoute(ctx, ctf.Route().Path)
+	ctf.SetUserContext(ctx)
+	return func() {
+		span.End()
+	}
 }
 
 func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/profile/add")
+		defer h.begin(ctf, "AddProfileHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/add")
 		req := profile.RequestAddProfile{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func AddProfileHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddProfileHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddProfileHandler, method Validate", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		filePath := fmt.Sprintf("static/uploads/profile/%s/images/defaultImage.jpg", req.UserName)
 		directoryPath := fmt.Sprintf("static/uploads/profile/%s/images", req.UserName)
 		if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
 			if err := os.MkdirAll(directoryPath, 0755); err != nil {
-				h.logger.Debug("error func AddProfileHandler, method MkdirAll by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method MkdirAll", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
 		form, err := ctf.MultipartForm()
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method MultipartForm by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddProfileHandler, method MultipartForm", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		imageFiles := form.File["image"]
@@ -61,48 +92,44 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 		for _, file := range imageFiles {
 			filePath = fmt.Sprintf("%s/%s", directoryPath, file.Filename)
 			if err := ctf.SaveFile(file, filePath); err != nil {
-				h.logger.Debug("error func AddProfileHandler, method SaveFile by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method SaveFile", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			fileImage, err := os.Open(filePath)
 			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method os.Open by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method os.Open", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			// The Decode function is used to read images from a file or other source and convert them into an image.
 			// Image structure
 			img, err := jpeg.Decode(fileImage)
 			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method jpeg.Decode by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method jpeg.Decode",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			newFileName := replaceExtension(file.Filename)
 			newFilePath := fmt.Sprintf("%s/%s", directoryPath, newFileName)
 			output, err := os.Create(directoryPath + "/" + newFileName)
 			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method os.Create by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method os.Create", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			defer output.Close()
 			options, err := encoder.NewLossyEncoderOptions(encoder.PresetDefault, 75)
 			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method NewLossyEncoderOptions by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler,"+
+					" method NewLossyEncoderOptions", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			if err := webp.Encode(output, img, options); err != nil {
-				h.logger.Debug("error func AddProfileHandler, method webp.Encode by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method webp.Encode",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			if err := os.Remove(filePath); err != nil {
-				h.logger.Debug("error func AddProfileHandler, method os.Remove by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method os.Remove", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			image := profile.ImageProfile{
 				Name:      file.Filename,
@@ -118,26 +145,6 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 			imagesFilePath = append(imagesFilePath, newFilePath)
 			imagesProfile = append(imagesProfile, &image)
 		}
-		height := 0
-		if req.Height != "" {
-			heightUint64, err := strconv.ParseUint(req.Height, 10, 8)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			height = int(heightUint64)
-		}
-		weight := 0
-		if req.Weight != "" {
-			weightUint64, err := strconv.ParseUint(req.Weight, 10, 8)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			weight = int(weightUint64)
-		}
 		profileDto := &profile.Profile{
 			SessionID:      req.SessionID,
 			DisplayName:    req.DisplayName,
@@ -145,8 +152,8 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 			Gender:         req.Gender,
 			Location:       req.Location,
 			Description:    req.Description,
-			Height:         uint8(height),
-			Weight:         uint8(weight),
+			Height:         req.Height,
+			Weight:         req.Weight,
 			IsDeleted:      false,
 			IsBlocked:      false,
 			IsPremium:      false,
@@ -157,7 +164,12 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 			LastOnline:     time.Now().UTC(),
 			Images:         imagesProfile,
 		}
-		newProfile, err := h.uc.Add(ctf.Context(), profileDto)
+		newProfile, err := h.uc.Add(ctf.UserContext(), profileDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method Add", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		h.metrics.CountEvent(metrics.EventProfileCreated)
 		for _, i := range profileDto.Images {
 			image := &profile.ImageProfile{
 				ProfileID: newProfile.ID,
@@ -171,117 +183,53 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 				IsPrimary: i.IsPrimary,
 				IsPrivate: i.IsPrivate,
 			}
-			_, err := h.uc.AddImage(ctf.Context(), image)
+			_, err := h.uc.AddImage(ctf.UserContext(), image)
 			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method AddImage by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method AddImage", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
-		}
-		telegramID, err := strconv.ParseUint(req.TelegramID, 10, 64)
-		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method ParseUint by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		allowsWriteToPm, err := strconv.ParseBool(req.AllowsWriteToPm)
-		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method ParseBool by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.metrics.CountEvent(metrics.EventImageUpload)
 		}
 		telegramDto := &profile.TelegramProfile{
 			ProfileID:       newProfile.ID,
-			TelegramID:      telegramID,
+			TelegramID:      req.TelegramID,
 			UserName:        req.TelegramUserName,
 			Firstname:       req.Firstname,
 			Lastname:        req.Lastname,
 			LanguageCode:    req.LanguageCode,
-			AllowsWriteToPm: allowsWriteToPm,
+			AllowsWriteToPm: req.AllowsWriteToPm,
 			QueryID:         req.QueryID,
 		}
-		_, err = h.uc.AddTelegram(ctf.Context(), telegramDto)
+		_, err = h.uc.AddTelegram(ctf.UserContext(), telegramDto)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method AddTelegram by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		ageFrom := 0
-		if req.AgeFrom != "" {
-			ageFromUint8, err := strconv.ParseUint(req.AgeFrom, 10, 8)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			ageFrom = int(ageFromUint8)
-		}
-		ageTo := 0
-		if req.AgeTo != "" {
-			ageToUint8, err := strconv.ParseUint(req.AgeTo, 10, 8)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			ageTo = int(ageToUint8)
-		}
-		distance := 0
-		if req.Distance != "" {
-			distance32, err := strconv.ParseUint(req.Distance, 10, 64)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			distance = int(distance32)
-		}
-		page := 0
-		if req.Page != "" {
-			page32, err := strconv.ParseUint(req.Page, 10, 64)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			page = int(page32)
-		}
-		size := 0
-		if req.Size != "" {
-			size32, err := strconv.ParseUint(req.Size, 10, 64)
-			if err != nil {
-				h.logger.Debug("error func AddProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			size = int(size32)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method AddTelegram", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		filterDto := &profile.FilterProfile{
 			ProfileID:    newProfile.ID,
 			SearchGender: req.SearchGender,
 			LookingFor:   req.LookingFor,
-			AgeFrom:      uint8(ageFrom),
-			AgeTo:        uint8(ageTo),
-			Distance:     uint64(distance),
-			Page:         uint64(page),
-			Size:         uint64(size),
+			AgeFrom:      req.AgeFrom,
+			AgeTo:        req.AgeTo,
+			Distance:     req.Distance,
+			Page:         req.Page,
+			Size:         req.Size,
 		}
-		_, err = h.uc.AddFilter(ctf.Context(), filterDto)
+		_, err = h.uc.AddFilter(ctf.UserContext(), filterDto)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method AddFilter by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method AddFilter", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		latitude, err := strconv.ParseFloat(req.Latitude, 64)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method ParseFloat height by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddProfileHandler, method ParseFloat height",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		longitude, err := strconv.ParseFloat(req.Longitude, 64)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method ParseFloat height by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddProfileHandler, method ParseFloat height",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		point := &profile.Point{
@@ -292,35 +240,33 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 			ProfileID: newProfile.ID,
 			Location:  point,
 		}
-		_, err = h.uc.AddNavigator(ctf.Context(), navigatorDto)
+		_, err = h.uc.AddNavigator(ctf.UserContext(), navigatorDto)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method AddNavigator by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method AddNavigator", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		p, err := h.uc.FindById(ctf.Context(), newProfile.ID)
+		p, err := h.uc.FindById(ctf.UserContext(), newProfile.ID)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		t, err := h.uc.FindTelegramByProfileID(ctf.Context(), p.ID)
+		t, err := h.uc.FindTelegramByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler,"+
+				" method FindTelegramByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), p.ID)
+		f, err := h.uc.FindFilterByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method FindFilterByProfileID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		i, err := h.uc.SelectListPublicImage(ctf.Context(), p.ID)
+		i, err := h.uc.SelectListPublicImage(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func AddProfileHandler, method SelectListPublicImage by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddProfileHandler, method SelectListPublicImage",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		response := &profile.Profile{
 			ID:             p.ID,
@@ -350,38 +296,39 @@ func (h *HandlerProfile) AddProfileHandler() fiber.Handler {
 
 func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("GET /api/v1/profile/list")
+		defer h.begin(ctf, "GetProfileListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/list")
 		params := profile.QueryParamsProfileList{}
 		if err := ctf.QueryParser(&params); err != nil {
-			h.logger.Debug("error func GetProfileListHandler, method QueryParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetProfileListHandler, method QueryParser",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindBySessionID(ctf.Context(), params.SessionID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), params.SessionID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileListHandler, method FindBySessionID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileListHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), p.ID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileListHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileListHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		latitudeStr := params.Latitude
 		longitudeStr := params.Longitude
 		if latitudeStr != "" && longitudeStr != "" {
 			latitude, err := strconv.ParseFloat(latitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileBySessionIDHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			longitude, err := strconv.ParseFloat(longitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileBySessionIDHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			point := &profile.Point{
@@ -392,25 +339,25 @@ func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 				ProfileID: p.ID,
 				Location:  point,
 			}
-			_, err = h.uc.UpdateNavigator(ctf.Context(), navigatorDto)
+			_, err = h.uc.UpdateNavigator(ctf.UserContext(), navigatorDto)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method UpdateNavigator by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+					" method UpdateNavigator", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), p.ID)
+		f, err := h.uc.FindFilterByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileListHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileListHandler,"+
+				" method FindFilterByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		ageFrom := 0
 		if params.AgeFrom != "" {
 			ageFromUint8, err := strconv.ParseUint(params.AgeFrom, 10, 8)
 			if err != nil {
-				h.logger.Debug("error func GetProfileListHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileListHandler,"+
+					" method ParseUint height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			ageFrom = int(ageFromUint8)
@@ -419,8 +366,8 @@ func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 		if params.AgeTo != "" {
 			ageToUint8, err := strconv.ParseUint(params.AgeTo, 10, 8)
 			if err != nil {
-				h.logger.Debug("error func GetProfileListHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileListHandler,"+
+					" method ParseUint height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			ageTo = int(ageToUint8)
@@ -429,8 +376,8 @@ func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 		if params.Distance != "" {
 			distance32, err := strconv.ParseUint(params.Distance, 10, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileListHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileListHandler,"+
+					" method ParseUint height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			distance = int(distance32)
@@ -446,17 +393,17 @@ func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 			Page:         params.Page,
 			Size:         params.Size,
 		}
-		_, err = h.uc.UpdateFilter(ctf.Context(), filterDto)
+		_, err = h.uc.UpdateFilter(ctf.UserContext(), filterDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method UpdateFilter by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method UpdateFilter",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		response, err := h.uc.SelectList(ctf.Context(), &params)
+		response, err := h.uc.SelectList(ctf.UserContext(), &params)
 		if err != nil {
-			h.logger.Debug("error func GetProfileListHandler, method SelectList by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileListHandler, method SelectList",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapOk(ctf, response)
 	}
@@ -464,39 +411,40 @@ func (h *HandlerProfile) GetProfileListHandler() fiber.Handler {
 
 func (h *HandlerProfile) GetProfileBySessionIDHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("GET /api/v1/profile/session/:id")
+		defer h.begin(ctf, "GetProfileBySessionIDHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/session/:id")
 		sessionID := ctf.Params("id")
 		params := profile.QueryParamsGetProfileByUserID{}
 		if err := ctf.QueryParser(&params); err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method QueryParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetProfileBySessionIDHandler, method QueryParser",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindBySessionID(ctf.Context(), sessionID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), sessionID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method FindBySessionID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+				" method FindBySessionID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), p.ID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+				" method UpdateLastOnline", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		latitudeStr := params.Latitude
 		longitudeStr := params.Longitude
 		if latitudeStr != "" && longitudeStr != "" {
 			latitude, err := strconv.ParseFloat(latitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileBySessionIDHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			longitude, err := strconv.ParseFloat(longitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileBySessionIDHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			point := &profile.Point{
@@ -507,30 +455,30 @@ func (h *HandlerProfile) GetProfileBySessionIDHandler() fiber.Handler {
 				ProfileID: p.ID,
 				Location:  point,
 			}
-			_, err = h.uc.UpdateNavigator(ctf.Context(), navigatorDto)
+			_, err = h.uc.UpdateNavigator(ctf.UserContext(), navigatorDto)
 			if err != nil {
-				h.logger.Debug("error func GetProfileBySessionIDHandler, method UpdateNavigator by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+					" method UpdateNavigator", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
-		t, err := h.uc.FindTelegramByProfileID(ctf.Context(), p.ID)
+		t, err := h.uc.FindTelegramByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+				" method FindTelegramByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), p.ID)
+		f, err := h.uc.FindFilterByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+				" method FindFilterByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		i, err := h.uc.SelectListPublicImage(ctf.Context(), p.ID)
+		i, err := h.uc.SelectListPublicImage(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileBySessionIDHandler, method SelectListPublicImage by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileBySessionIDHandler,"+
+				" method SelectListPublicImage", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		response := &profile.ResponseProfile{
 			ID:        p.ID,
@@ -562,51 +510,62 @@ func (h *HandlerProfile) GetProfileBySessionIDHandler() fiber.Handler {
 
 func (h *HandlerProfile) GetProfileDetailHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("GET /api/v1/profile/detail/:id")
+		defer h.begin(ctf, "GetProfileDetailHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/detail/:id")
 		idStr := ctf.Params("id")
 		profileID, err := strconv.ParseUint(idStr, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method ParseUint by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetProfileDetailHandler, method ParseUint",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		params := profile.QueryParamsGetProfileDetail{}
 		if err := ctf.QueryParser(&params); err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method QueryParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetProfileDetailHandler, method QueryParser",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindById(ctf.Context(), profileID)
+		p, err := h.uc.FindById(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler, method FindById",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		v, err := h.uc.FindBySessionID(ctf.Context(), params.ViewerID)
+		v, err := h.uc.FindBySessionID(ctf.UserContext(), params.ViewerID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method FindBySessionID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), v.ID)
+		// The profiles blocked with the viewer in any direction are hidden as if they did not exist
+		isBlocked, err := h.uc.CheckIfBlockExists(ctf.UserContext(), p.ID, v.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetProfileDetailHandler,"+
+				" method CheckIfBlockExists", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		if isBlocked {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), v.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+				" method UpdateLastOnline", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
 		latitudeStr := params.Latitude
 		longitudeStr := params.Longitude
 		if latitudeStr != "" && longitudeStr != "" {
 			latitude, err := strconv.ParseFloat(latitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileDetailHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileDetailHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			longitude, err := strconv.ParseFloat(longitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func GetProfileDetailHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func GetProfileDetailHandler,"+
+					" method ParseFloat height", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			point := &profile.Point{
@@ -617,42 +576,57 @@ func (h *HandlerProfile) GetProfileDetailHandler() fiber.Handler {
 				ProfileID: v.ID,
 				Location:  point,
 			}
-			_, err = h.uc.UpdateNavigator(ctf.Context(), navigatorDto)
+			_, err = h.uc.UpdateNavigator(ctf.UserContext(), navigatorDto)
 			if err != nil {
-				h.logger.Debug("error func GetProfileDetailHandler, method UpdateNavigator by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+					" method UpdateNavigator", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
+			}
+		}
+		// Invisible viewers leave no visit
+		if v.ID != p.ID && !v.IsInvisible {
+			visitDto := &profile.VisitProfile{
+				ProfileID: p.ID,
+				ViewerID:  v.ID,
+				CreatedAt: time.Now().UTC(),
+				UpdatedAt: time.Now().UTC(),
+			}
+			_, err = h.uc.AddVisit(ctf.UserContext(), visitDto)
+			if err != nil {
+				h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler, method AddVisit",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
-		t, err := h.uc.FindTelegramByProfileID(ctf.Context(), profileID)
+		t, err := h.uc.FindTelegramByProfileID(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+				" method FindTelegramByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), profileID)
+		f, err := h.uc.FindFilterByProfileID(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+				" method FindFilterByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		n, err := h.uc.FindNavigatorByProfileIDAndViewerID(ctf.Context(), p.ID, v.ID)
+		n, err := h.uc.FindNavigatorByProfileIDAndViewerID(ctf.UserContext(), p.ID, v.ID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method FindNavigatorByProfileIDAndViewerID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+				" method FindNavigatorByProfileIDAndViewerID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		i, err := h.uc.SelectListPublicImage(ctf.Context(), profileID)
+		i, err := h.uc.SelectListPublicImage(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, method SelectListPublicImage by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler,"+
+				" method SelectListPublicImage", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		l, isExistLike, err := h.uc.FindLikeByHumanID(ctf.Context(), v.ID, profileID)
+		l, isExistLike, err := h.uc.FindLikeByHumanID(ctf.UserContext(), v.ID, profileID)
 		if err != nil {
-			h.logger.Debug("error func GetProfileDetailHandler, FindLikeByHumanID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileDetailHandler, FindLikeByHumanID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		var lDao *profile.ResponseLikeProfile
 		if isExistLike {
@@ -688,18 +662,20 @@ func (h *HandlerProfile) GetProfileDetailHandler() fiber.Handler {
 			IsShowDistance: p.IsShowDistance,
 			IsInvisible:    p.IsInvisible,
 			IsOnline:       false,
+			IsVerified:     p.IsVerified,
 			CreatedAt:      p.CreatedAt,
 			UpdatedAt:      p.UpdatedAt,
-			LastOnline:     p.LastOnline,
+			LastOnline:     nil,
 			Images:         i,
 			Telegram:       t,
 			Navigator:      n,
 			Filter:         f,
 			Like:           lDao,
 		}
-		elapsed := time.Since(p.LastOnline)
-		if elapsed.Minutes() < 5 {
-			response.IsOnline = true
+		// The online status of an invisible profile is hidden from everyone except the profile itself
+		if !p.IsInvisible || p.ID == v.ID {
+			response.LastOnline = &p.LastOnline
+			response.IsOnline = time.Since(p.LastOnline).Minutes() < 5
 		}
 		return r.WrapOk(ctf, response)
 	}
@@ -707,76 +683,64 @@ func (h *HandlerProfile) GetProfileDetailHandler() fiber.Handler {
 
 func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/profile/edit")
+		defer h.begin(ctf, "UpdateProfileHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/edit")
 		req := profile.RequestUpdateProfile{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		profileID, err := strconv.ParseUint(req.ID, 10, 64)
-		if err != nil {
-			h.logger.Debug(
-				"error func UpdateProfileHandler, method ParseUint roomIdStr by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler, method Validate", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		profileInDB, err := h.uc.FindById(ctf.Context(), profileID)
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		profileID := req.ID
+		profileInDB, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler, method FindById", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		if profileInDB.IsDeleted == true {
-			msg := errors.Wrap(err, "user has already been deleted")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
+			err = appError.NewNotFound("user has already been deleted")
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		if profileInDB.IsBlocked == true {
-			msg := errors.Wrap(err, "user has already been blocked")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
+			err = appError.NewNotFound("user has already been blocked")
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), profileInDB.ID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), profileInDB.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		filePath := fmt.Sprintf("static/uploads/profile/%s/images/defaultImage.jpg", req.UserName)
 		directoryPath := fmt.Sprintf("static/uploads/profile/%s/images", req.UserName)
 		if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
 			if err := os.MkdirAll(directoryPath, 0755); err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method MkdirAll by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method MkdirAll",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
 		form, err := ctf.MultipartForm()
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method MultipartForm by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler, method MultipartForm",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		height := 0
-		if req.Height != "" {
-			heightUint64, err := strconv.ParseUint(req.Height, 10, 8)
+		isInvisible := profileInDB.IsInvisible
+		if req.IsInvisible != "" {
+			isInvisible, err = strconv.ParseBool(req.IsInvisible)
 			if err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler,"+
+					" method ParseBool isInvisible", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
-			height = int(heightUint64)
 		}
-		weight := 0
-		if req.Weight != "" {
-			weightUint64, err := strconv.ParseUint(req.Weight, 10, 8)
-			if err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method ParseUint height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
-			}
-			weight = int(weightUint64)
+		if isInvisible && !h.uc.IsEntitled(profileInDB, profile.FeatureInvisible) {
+			err := appError.NewForbidden("invisible mode is available only with premium")
+			return r.WrapError(ctf, err, http.StatusForbidden)
 		}
 		imageFiles := form.File["image"]
 		profileDto := &profile.Profile{}
@@ -786,48 +750,48 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 			for _, file := range imageFiles {
 				filePath = fmt.Sprintf("%s/%s", directoryPath, file.Filename)
 				if err := ctf.SaveFile(file, filePath); err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method SaveFile by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method SaveFile",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				fileImage, err := os.Open(filePath)
 				if err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method os.Open by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method os.Open",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				// The Decode function is used to read images from a file or other source and convert them into an image.
 				// Image structure
 				img, err := jpeg.Decode(fileImage)
 				if err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method jpeg.Decode by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+						" method jpeg.Decode", zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				newFileName := replaceExtension(file.Filename)
 				newFilePath := fmt.Sprintf("%s/%s", directoryPath, newFileName)
 				output, err := os.Create(directoryPath + "/" + newFileName)
 				if err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method os.Create by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method os.Create",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				defer output.Close()
 				options, err := encoder.NewLossyEncoderOptions(encoder.PresetDefault, 75)
 				if err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method NewLossyEncoderOptions by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+						" method NewLossyEncoderOptions", zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				if err := webp.Encode(output, img, options); err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method webp.Encode by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+						" method webp.Encode", zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				if err := os.Remove(filePath); err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method os.Remove by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method os.Remove",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				image := profile.ImageProfile{
 					Name:      file.Filename,
@@ -851,13 +815,13 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 				Gender:         req.Gender,
 				Location:       req.Location,
 				Description:    req.Description,
-				Height:         uint8(height),
-				Weight:         uint8(weight),
+				Height:         req.Height,
+				Weight:         req.Weight,
 				IsDeleted:      profileInDB.IsDeleted,
 				IsBlocked:      profileInDB.IsBlocked,
 				IsPremium:      profileInDB.IsPremium,
 				IsShowDistance: profileInDB.IsShowDistance,
-				IsInvisible:    profileInDB.IsInvisible,
+				IsInvisible:    isInvisible,
 				CreatedAt:      profileInDB.CreatedAt,
 				UpdatedAt:      time.Now().UTC(),
 				LastOnline:     time.Now().UTC(),
@@ -872,26 +836,40 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 				Gender:         req.Gender,
 				Location:       req.Location,
 				Description:    req.Description,
-				Height:         uint8(height),
-				Weight:         uint8(weight),
+				Height:         req.Height,
+				Weight:         req.Weight,
 				IsDeleted:      profileInDB.IsDeleted,
 				IsBlocked:      profileInDB.IsBlocked,
 				IsPremium:      profileInDB.IsPremium,
 				IsShowDistance: profileInDB.IsShowDistance,
-				IsInvisible:    profileInDB.IsInvisible,
+				IsInvisible:    isInvisible,
 				CreatedAt:      profileInDB.CreatedAt,
 				UpdatedAt:      time.Now().UTC(),
 				LastOnline:     time.Now().UTC(),
 			}
 		}
-		profileUpdated, err := h.uc.Update(ctf.Context(), profileDto)
+		profileUpdated, err := h.uc.Update(ctf.UserContext(), profileDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method Update", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// The changed birthday may hide the age of a minor, so the profile waits for a moderator
+		if !profile.IsSameDate(profileInDB.Birthday, req.Birthday) {
+			_, err := h.uc.RequestAgeVerification(
+				ctf.UserContext(), profileUpdated.ID, profile.AgeVerificationReasonBirthdayChange)
+			if err != nil {
+				h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+					" method RequestAgeVerification", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
+			}
+		}
 		if len(imageFiles) > 0 {
 			for _, i := range profileDto.Images {
-				exists, imageID, err := h.uc.CheckIfCommonImageExists(ctf.Context(), profileUpdated.ID, i.Name)
+				exists, imageID, err := h.uc.CheckIfCommonImageExists(ctf.UserContext(), profileUpdated.ID, i.Name)
 				if err != nil {
-					h.logger.Debug("error func UpdateProfileHandler, method CheckIfCommonImageExists by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
+					h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+						" method CheckIfCommonImageExists", zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
 				}
 				if !exists {
 					image := &profile.ImageProfile{
@@ -906,12 +884,13 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 						IsPrimary: i.IsPrimary,
 						IsPrivate: i.IsPrivate,
 					}
-					_, err := h.uc.AddImage(ctf.Context(), image)
+					_, err := h.uc.AddImage(ctf.UserContext(), image)
 					if err != nil {
-						h.logger.Debug("error func UpdateProfileHandler, method AddImage by path"+
-							" internal/handler/profile/profile.go", zap.Error(err))
-						return r.WrapError(ctf, err, http.StatusBadRequest)
+						h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+							" method AddImage", zap.Error(err))
+						return r.WrapError(ctf, err, http.StatusInternalServerError)
 					}
+					h.metrics.CountEvent(metrics.EventImageUpload)
 				} else {
 					image := &profile.ImageProfile{
 						ID:        imageID,
@@ -926,81 +905,74 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 						IsPrimary: i.IsPrimary,
 						IsPrivate: i.IsPrivate,
 					}
-					_, err := h.uc.UpdateImage(ctf.Context(), image)
+					_, err := h.uc.UpdateImage(ctf.UserContext(), image)
 					if err != nil {
-						h.logger.Debug("error func UpdateProfileHandler, method UpdateImage by path"+
-							" internal/handler/profile/profile.go", zap.Error(err))
-						return r.WrapError(ctf, err, http.StatusBadRequest)
+						h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+							" method UpdateImage", zap.Error(err))
+						return r.WrapError(ctf, err, http.StatusInternalServerError)
 					}
 				}
 			}
 		}
-		telegramID, err := strconv.ParseUint(req.TelegramID, 10, 64)
+		t, err := h.uc.FindTelegramByProfileID(ctf.UserContext(), profileUpdated.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method ParseUint roomIdStr by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		allowsWriteToPm, err := strconv.ParseBool(req.AllowsWriteToPm)
-		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method ParseBool roomIdStr by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		t, err := h.uc.FindTelegramByProfileID(ctf.Context(), profileUpdated.ID)
-		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+				" method FindTelegramByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		telegramDto := &profile.TelegramProfile{
 			ID:              t.ID,
 			ProfileID:       profileUpdated.ID,
-			TelegramID:      telegramID,
+			TelegramID:      req.TelegramID,
 			UserName:        req.TelegramUserName,
 			Firstname:       req.Firstname,
 			Lastname:        req.Lastname,
 			LanguageCode:    req.LanguageCode,
-			AllowsWriteToPm: allowsWriteToPm,
+			AllowsWriteToPm: req.AllowsWriteToPm,
 			QueryID:         req.QueryID,
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), profileUpdated.ID)
+		f, err := h.uc.FindFilterByProfileID(ctf.UserContext(), profileUpdated.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+				" method FindFilterByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		_, err = h.uc.UpdateTelegram(ctf.Context(), telegramDto)
+		_, err = h.uc.UpdateTelegram(ctf.UserContext(), telegramDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method UpdateTelegram by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method UpdateTelegram",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		filterDto := &profile.FilterProfile{
 			ID:           f.ID,
 			ProfileID:    profileUpdated.ID,
 			SearchGender: req.SearchGender,
 			LookingFor:   req.LookingFor,
+			AgeFrom:      req.AgeFrom,
+			AgeTo:        req.AgeTo,
+			Distance:     req.Distance,
+			Page:         f.Page,
+			Size:         f.Size,
 		}
-		_, err = h.uc.UpdateFilter(ctf.Context(), filterDto)
+		_, err = h.uc.UpdateFilter(ctf.UserContext(), filterDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method UpdateFilter by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method UpdateFilter",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		latitudeStr := req.Latitude
 		longitudeStr := req.Longitude
 		if latitudeStr != "" && longitudeStr != "" {
 			latitude, err := strconv.ParseFloat(latitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler,"+
+					" method ParseFloat latitude", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			longitude, err := strconv.ParseFloat(longitudeStr, 64)
 			if err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method ParseFloat height by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
+				h.logger.With(ctf.UserContext()).Warn("error func UpdateProfileHandler,"+
+					" method ParseFloat longitude", zap.Error(err))
 				return r.WrapError(ctf, err, http.StatusBadRequest)
 			}
 			point := &profile.Point{
@@ -1011,35 +983,29 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 				ProfileID: profileID,
 				Location:  point,
 			}
-			_, err = h.uc.UpdateNavigator(ctf.Context(), navigatorDto)
+			_, err = h.uc.UpdateNavigator(ctf.UserContext(), navigatorDto)
 			if err != nil {
-				h.logger.Debug("error func UpdateProfileHandler, method UpdateNavigator by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+					" method UpdateNavigator", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 		}
+		p, err := h.uc.FindById(ctf.UserContext(), profileUpdated.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method UpdateNavigator by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		p, err := h.uc.FindById(ctf.Context(), profileUpdated.ID)
-		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		t, err = h.uc.FindTelegramByProfileID(ctf.Context(), p.ID)
+		t, err = h.uc.FindTelegramByProfileID(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error(""+
+				"error func UpdateProfileHandler method FindTelegramByProfileID", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		i, err := h.uc.SelectListPublicImage(ctf.Context(), p.ID)
+		i, err := h.uc.SelectListPublicImage(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func UpdateProfileHandler, method SelectListPublicImage by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateProfileHandler,"+
+				" method SelectListPublicImage", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		response := &profile.Profile{
 			ID:             p.ID,
@@ -1069,162 +1035,78 @@ func (h *HandlerProfile) UpdateProfileHandler() fiber.Handler {
 
 func (h *HandlerProfile) DeleteProfileHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/profile/delete")
+		defer h.begin(ctf, "DeleteProfileHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/delete")
 		req := profile.RequestDeleteProfile{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		profileID, err := strconv.ParseUint(req.ID, 10, 64)
-		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method ParseUint roomIdStr by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		profileInDB, err := h.uc.FindById(ctf.Context(), profileID)
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusNotFound)
-		}
-		if profileInDB.IsDeleted == true {
-			msg := errors.Wrap(err, "user has already been deleted")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
-			return r.WrapError(ctf, err, http.StatusNotFound)
-		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), profileInDB.ID)
-		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		imageList, err := h.uc.SelectListImage(ctf.Context(), profileID)
-		if len(imageList) > 0 {
-			for _, i := range imageList {
-				filePath := i.Url
-				if err := os.Remove(filePath); err != nil {
-					h.logger.Debug("error func DeleteProfileHandler, method Remove by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
-				}
-				imageDTO := &profile.ImageProfile{
-					ID:        i.ID,
-					ProfileID: i.ProfileID,
-					Name:      "",
-					Url:       "",
-					Size:      0,
-					CreatedAt: i.CreatedAt,
-					UpdatedAt: time.Now().UTC(),
-					IsDeleted: true,
-					IsBlocked: i.IsBlocked,
-					IsPrimary: i.IsPrimary,
-					IsPrivate: i.IsPrivate,
-				}
-				_, err := h.uc.DeleteImage(ctf.Context(), imageDTO)
-				if err != nil {
-					h.logger.Debug("error func DeleteProfileHandler, method DeleteImage by path"+
-						" internal/handler/profile/profile.go", zap.Error(err))
-					return r.WrapError(ctf, err, http.StatusBadRequest)
-				}
-			}
-		}
-		t, err := h.uc.FindTelegramByProfileID(ctf.Context(), profileInDB.ID)
-		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method FindTelegramByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method Validate", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		telegramDto := &profile.TelegramProfile{
-			ID:              t.ID,
-			ProfileID:       profileInDB.ID,
-			TelegramID:      0,
-			UserName:        "",
-			Firstname:       "",
-			Lastname:        "",
-			LanguageCode:    "",
-			AllowsWriteToPm: false,
-			QueryID:         "",
-		}
-		_, err = h.uc.DeleteTelegram(ctf.Context(), telegramDto)
+		profileID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method DeleteTelegram by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		n, err := h.uc.FindNavigatorByProfileID(ctf.Context(), profileInDB.ID)
+		sessionProfile, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method FindNavigatorByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		point := &profile.Point{
-			Latitude:  0.0,
-			Longitude: 0.0,
-		}
-		navigatorDto := &profile.NavigatorProfile{
-			ID:        n.ID,
-			ProfileID: profileInDB.ID,
-			Location:  point,
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
-		_, err = h.uc.DeleteNavigator(ctf.Context(), navigatorDto)
-		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method DeleteNavigator by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+		// The profile is deleted by its owner only, the erasure job wipes it after the grace period
+		if sessionProfile.ID != profileID {
+			err := appError.NewForbidden("profile belongs to another user")
+			return r.WrapError(ctf, err, http.StatusForbidden)
 		}
-		f, err := h.uc.FindFilterByProfileID(ctf.Context(), profileInDB.ID)
+		profileInDB, err := h.uc.FindById(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method FindFilterByProfileID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
-		filterDto := &profile.FilterProfile{
-			ID:           f.ID,
-			ProfileID:    profileInDB.ID,
-			SearchGender: "",
-			LookingFor:   "",
-			AgeFrom:      0,
-			AgeTo:        0,
-			Distance:     0,
-			Page:         0,
-			Size:         0,
+		if profileInDB.IsDeleted == true {
+			err = appError.NewNotFound("user has already been deleted")
+			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
-		_, err = h.uc.DeleteFilter(ctf.Context(), filterDto)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), profileInDB.ID)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method DeleteFilter by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteProfileHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
+		// The personal data is kept during profile.DeletionGracePeriod so that the profile can be restored,
+		// it is erased by the profile erasure job after that
 		profileDto := &profile.Profile{
 			ID:             profileID,
-			SessionID:      "",
-			DisplayName:    "",
+			SessionID:      profileInDB.SessionID,
+			DisplayName:    profileInDB.DisplayName,
 			Birthday:       profileInDB.Birthday,
-			Gender:         "",
-			Location:       "",
-			Description:    "",
-			Height:         0,
-			Weight:         0,
+			Gender:         profileInDB.Gender,
+			Location:       profileInDB.Location,
+			Description:    profileInDB.Description,
+			Height:         profileInDB.Height,
+			Weight:         profileInDB.Weight,
 			IsDeleted:      true,
-			IsBlocked:      false,
-			IsPremium:      false,
-			IsShowDistance: false,
-			IsInvisible:    false,
+			IsBlocked:      profileInDB.IsBlocked,
+			IsPremium:      profileInDB.IsPremium,
+			IsShowDistance: profileInDB.IsShowDistance,
+			IsInvisible:    profileInDB.IsInvisible,
 			CreatedAt:      profileInDB.CreatedAt,
 			UpdatedAt:      time.Now().UTC(),
 			LastOnline:     time.Now().UTC(),
 		}
-		_, err = h.uc.Delete(ctf.Context(), profileDto)
+		_, err = h.uc.Delete(ctf.UserContext(), profileDto)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method Delete by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteProfileHandler, method Delete", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		p, err := h.uc.FindById(ctf.Context(), profileID)
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteProfileHandler, method FindById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		p, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileHandler, method FindById", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		response := &profile.Profile{
@@ -1254,37 +1136,87 @@ func (h *HandlerProfile) DeleteProfileHandler() fiber.Handler {
 	}
 }
 
+func (h *HandlerProfile) RestoreProfileHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "RestoreProfileHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/restore")
+		req := profile.RequestRestoreProfile{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RestoreProfileHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RestoreProfileHandler, method Validate", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		profileID, err := strconv.ParseUint(req.ID, 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RestoreProfileHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		profileInDB, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RestoreProfileHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusNotFound)
+		}
+		// The profile is restored by its owner only, the session is kept until the profile is erased
+		if profileInDB.SessionID != req.SessionID {
+			err := appError.NewForbidden("profile belongs to another user")
+			return r.WrapError(ctf, err, http.StatusForbidden)
+		}
+		if !profileInDB.IsDeleted {
+			err := appError.NewValidation("profile has not been deleted")
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		isRestored, err := h.uc.Restore(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func RestoreProfileHandler, method Restore", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isRestored {
+			err := errorDomain.NewCustomError(errors.New("profile can no longer be restored"), http.StatusGone)
+			return r.WrapError(ctf, err, http.StatusGone)
+		}
+		p, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RestoreProfileHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusNotFound)
+		}
+		return r.WrapOk(ctf, p)
+	}
+}
+
 func (h *HandlerProfile) DeleteProfileImageHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/profile/image/delete")
+		defer h.begin(ctf, "DeleteProfileImageHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/image/delete")
 		req := profile.RequestDeleteProfileImage{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteProfileImageHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileImageHandler, method BodyParser",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		imageID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileImageHandler, method ParseUint roomIdStr by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileImageHandler,"+
+				" method ParseUint roomIdStr", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		imageInDB, err := h.uc.FindImageById(ctf.Context(), imageID)
+		imageInDB, err := h.uc.FindImageById(ctf.UserContext(), imageID)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileImageHandler, method FindImageById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteProfileImageHandler, method FindImageById",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		if imageInDB.IsDeleted == true {
-			msg := errors.Wrap(err, "image has already been deleted")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
+			err = appError.NewNotFound("image has already been deleted")
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		filePath := imageInDB.Url
 		if err := os.Remove(filePath); err != nil {
-			h.logger.Debug("error func DeleteProfileImageHandler, method Remove by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteProfileImageHandler, method Remove",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		imageDTO := &profile.ImageProfile{
 			ID:        imageInDB.ID,
@@ -1299,11 +1231,11 @@ func (h *HandlerProfile) DeleteProfileImageHandler() fiber.Handler {
 			IsPrimary: imageInDB.IsPrimary,
 			IsPrivate: imageInDB.IsPrivate,
 		}
-		response, err := h.uc.DeleteImage(ctf.Context(), imageDTO)
+		response, err := h.uc.DeleteImage(ctf.UserContext(), imageDTO)
 		if err != nil {
-			h.logger.Debug("error func DeleteProfileImageHandler, method DeleteImage by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteProfileImageHandler, method DeleteImage",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, response)
 	}
@@ -1326,45 +1258,82 @@ func (h *HandlerProfile) Distance(lat1, lon1, lat2, lon2 float64) float64 {
 
 func (h *HandlerProfile) AddReviewHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/review/add")
+		defer h.begin(ctf, "AddReviewHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/add")
 		req := profile.RequestAddReview{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func AddReviewHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		profileID, err := strconv.ParseUint(req.ProfileID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func AddReviewHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), profileID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		p, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		humanID, err := strconv.ParseUint(req.HumanID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func AddReviewHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewHandler, method ParseUint humanID",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		if humanID == profileID {
+			err := appError.NewValidation("profile cannot review itself")
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		_, isExist, err := h.uc.FindReviewByHumanID(ctf.UserContext(), profileID, humanID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewHandler, method FindReviewByHumanID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if isExist {
+			err := appError.NewConflict("review has already been added")
+			return r.WrapError(ctf, err, http.StatusConflict)
+		}
 		rating, err := strconv.ParseFloat(req.Rating, 32)
 		if err != nil {
-			h.logger.Debug("error func AddReviewHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		reviewDto := &profile.ReviewProfile{
 			ProfileID:  profileID,
+			HumanID:    humanID,
 			Message:    req.Message,
 			Rating:     float32(rating),
 			HasDeleted: false,
 			HasEdited:  false,
+			Status:     h.uc.GetReviewStatus(req.Message),
 			CreatedAt:  time.Now().UTC(),
 			UpdatedAt:  time.Now().UTC(),
 		}
-		review, err := h.uc.AddReview(ctf.Context(), reviewDto)
+		isAllowed, err := h.reserveQuota(ctf, p, profile.QuotaActionReview)
 		if err != nil {
-			h.logger.Debug("error func AddReviewHandler, method AddReview by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewHandler, method reserveQuota", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isAllowed {
+			err := errorDomain.NewCustomError(errors.New("review limit has been reached"),
+				http.StatusTooManyRequests)
+			return r.WrapError(ctf, err, http.StatusTooManyRequests)
+		}
+		review, err := h.uc.AddReview(ctf.UserContext(), reviewDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewHandler, method AddReview", zap.Error(err))
+			h.releaseQuota(ctf, p, profile.QuotaActionReview)
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, review)
 	}
@@ -1372,63 +1341,73 @@ func (h *HandlerProfile) AddReviewHandler() fiber.Handler {
 
 func (h *HandlerProfile) UpdateReviewHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/review/update")
+		defer h.begin(ctf, "UpdateReviewHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/update")
 		req := profile.RequestUpdateReview{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateReviewHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		reviewID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		profileID, err := strconv.ParseUint(req.ProfileID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), profileID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateReviewHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		reviewInDB, err := h.uc.FindReviewById(ctf.Context(), reviewID)
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method FindReviewById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		reviewInDB, err := h.uc.FindReviewById(ctf.UserContext(), reviewID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateReviewHandler, method FindReviewById",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		if reviewInDB.HasDeleted == true {
-			msg := errors.Wrap(err, "review has already been deleted")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
+			err = appError.NewNotFound("review has already been deleted")
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
+		if reviewInDB.ProfileID != profileID {
+			err := appError.NewForbidden("review belongs to another profile")
+			return r.WrapError(ctf, err, http.StatusForbidden)
+		}
 		rating, err := strconv.ParseFloat(req.Rating, 32)
 		if err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		// The edit does not take the review out of moderation
+		status := h.uc.GetReviewStatus(req.Message)
+		if reviewInDB.Status != profile.ReviewStatusPublished {
+			status = profile.ReviewStatusPending
+		}
 		reviewDto := &profile.ReviewProfile{
 			ID:         reviewID,
 			ProfileID:  profileID,
+			HumanID:    reviewInDB.HumanID,
 			Message:    req.Message,
 			Rating:     float32(rating),
 			HasDeleted: reviewInDB.HasDeleted,
 			HasEdited:  true,
+			Status:     status,
 			CreatedAt:  reviewInDB.CreatedAt,
 			UpdatedAt:  time.Now().UTC(),
 		}
-		review, err := h.uc.UpdateReview(ctf.Context(), reviewDto)
+		review, err := h.uc.UpdateReview(ctf.UserContext(), reviewDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateReviewHandler, method UpdateReview by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateReviewHandler, method UpdateReview",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, review)
 	}
@@ -1436,45 +1415,46 @@ func (h *HandlerProfile) UpdateReviewHandler() fiber.Handler {
 
 func (h *HandlerProfile) DeleteReviewHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/review/delete")
+		defer h.begin(ctf, "DeleteReviewHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/delete")
 		req := profile.RequestDeleteReview{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteReviewHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteReviewHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		reviewID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func DeleteReviewHandler, method ParseUint roomIdStr by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteReviewHandler, method ParseUint roomIdStr",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		reviewInDB, err := h.uc.FindReviewById(ctf.Context(), reviewID)
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteReviewHandler, method FindReviewById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		reviewInDB, err := h.uc.FindReviewById(ctf.UserContext(), reviewID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteReviewHandler, method FindReviewById",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		if reviewInDB.HasDeleted == true {
-			msg := errors.Wrap(err, "review has already been deleted")
-			err = errorDomain.NewCustomError(msg, http.StatusNotFound)
+			err = appError.NewNotFound("review has already been deleted")
 			return r.WrapError(ctf, err, http.StatusNotFound)
 		}
 		reviewDto := &profile.ReviewProfile{
 			ID:         reviewID,
 			ProfileID:  reviewInDB.ProfileID,
+			HumanID:    reviewInDB.HumanID,
 			Message:    reviewInDB.Message,
 			Rating:     reviewInDB.Rating,
 			HasDeleted: true,
 			HasEdited:  reviewInDB.HasEdited,
+			Status:     reviewInDB.Status,
 			CreatedAt:  reviewInDB.CreatedAt,
 			UpdatedAt:  time.Now().UTC(),
 		}
-		review, err := h.uc.DeleteReview(ctf.Context(), reviewDto)
+		review, err := h.uc.DeleteReview(ctf.UserContext(), reviewDto)
 		if err != nil {
-			h.logger.Debug("error func DeleteReviewHandler, method UpdateReview by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteReviewHandler, method UpdateReview",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, review)
 	}
@@ -1482,136 +1462,334 @@ func (h *HandlerProfile) DeleteReviewHandler() fiber.Handler {
 
 func (h *HandlerProfile) GetReviewByIDHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("GET /api/v1/review/detail/:id")
+		defer h.begin(ctf, "GetReviewByIDHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/review/detail/:id")
 		idStr := ctf.Params("id")
 		id, err := strconv.ParseUint(idStr, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func GetReviewByIDHandler, method ParseUint by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetReviewByIDHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		response, err := h.uc.FindReviewById(ctf.Context(), id)
-		if err != nil {
-			h.logger.Debug("error func GetProfileByIDHandler, method FindReviewById by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		params := profile.QueryParamsReviewDetail{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetReviewByIDHandler, method QueryParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		response, err := h.uc.FindReviewById(ctf.UserContext(), id)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetProfileByIDHandler, method FindReviewById",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// The pending and the rejected reviews are held by the moderation, only their author can read them
+		if response.Status != profile.ReviewStatusPublished &&
+			(params.SessionID == "" || params.SessionID != response.SessionID) {
+			err := appError.NewNotFound("review is not found")
+			return r.WrapError(ctf, err, http.StatusNotFound)
+		}
 		return r.WrapOk(ctf, response)
 	}
 }
 
 func (h *HandlerProfile) GetReviewListHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("GET /api/v1/review/list")
+		defer h.begin(ctf, "GetReviewListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/review/list")
 		params := profile.QueryParamsReviewList{}
 		if err := ctf.QueryParser(&params); err != nil {
-			h.logger.Debug("error func GetReviewListHandler, method QueryParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetReviewListHandler, method QueryParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		profileID, err := strconv.ParseUint(params.ProfileID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func GetReviewListHandler, method ParseUint roomIdStr by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetReviewListHandler,"+
+				" method ParseUint roomIdStr", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), profileID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), profileID)
 		if err != nil {
-			h.logger.Debug("error func GetReviewListHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetReviewListHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		response, err := h.uc.SelectReviewList(ctf.Context(), &params)
+		response, err := h.uc.SelectReviewList(ctf.UserContext(), &params)
 		if err != nil {
-			h.logger.Debug("error func GetReviewListHandler, method SelectList by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func GetReviewListHandler, method SelectList", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapOk(ctf, response)
 	}
 }
 
-func (h *HandlerProfile) AddLikeHandler() fiber.Handler {
+func (h *HandlerProfile) AddReviewReportHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/like/add")
-		req := profile.RequestAddLike{}
+		defer h.begin(ctf, "AddReviewReportHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/report")
+		req := profile.RequestAddReviewReport{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func AddLikeHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewReportHandler, method BodyParser",
+				zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		humanID, err := strconv.ParseUint(req.HumanID, 10, 64)
+		reviewID, err := strconv.ParseUint(req.ReviewID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func AddLikeHandler, method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewReportHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindBySessionID(ctf.Context(), req.SessionID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
 		if err != nil {
-			h.logger.Debug("error func AddLikeHandler, method FindByKeycloakID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewReportHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewReportHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), p.ID)
+		reviewInDB, err := h.uc.FindReviewById(ctf.UserContext(), reviewID)
 		if err != nil {
-			h.logger.Debug("error func AddLikeHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddReviewReportHandler, method FindReviewById",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusNotFound)
+		}
+		if reviewInDB.HasDeleted {
+			err := appError.NewNotFound("review has already been deleted")
+			return r.WrapError(ctf, err, http.StatusNotFound)
+		}
+		if reviewInDB.ProfileID == p.ID {
+			err := appError.NewValidation("profile cannot report its own review")
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		likeDto := &profile.LikeProfile{
+		reportDto := &profile.ReviewReportProfile{
+			ReviewID:  reviewID,
 			ProfileID: p.ID,
-			HumanID:   humanID,
-			IsLiked:   true,
+			Reason:    req.Reason,
 			CreatedAt: time.Now().UTC(),
 			UpdatedAt: time.Now().UTC(),
 		}
-		like, err := h.uc.AddLike(ctf.Context(), likeDto)
+		report, err := h.uc.AddReviewReport(ctf.UserContext(), reportDto)
 		if err != nil {
-			h.logger.Debug("error func AddLikeHandler, method AddLike by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddReviewReportHandler, method AddReviewReport",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		return r.WrapCreated(ctf, like)
+		return r.WrapCreated(ctf, report)
 	}
 }
 
-func (h *HandlerProfile) DeleteLikeHandler() fiber.Handler {
+func (h *HandlerProfile) GetReviewModerationListHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/like/delete")
-		req := profile.RequestDeleteLike{}
-		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func DeleteLikeHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+		defer h.begin(ctf, "GetReviewModerationListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/review/moderation/list")
+		params := profile.QueryParamsReviewModerationList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetReviewModerationListHandler,"+
+				" method QueryParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		likeID, err := strconv.ParseUint(req.ID, 10, 64)
-		if err != nil {
-			h.logger.Debug("error func DeleteLikeHandler, method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+		if params.Status == "" {
+			params.Status = profile.ReviewStatusPending
 		}
-		l, isExistLike, err := h.uc.FindLikeByID(ctf.Context(), likeID)
+		response, err := h.uc.SelectListReviewByStatus(ctf.UserContext(), &params)
 		if err != nil {
-			h.logger.Debug("error func DeleteLikeHandler, method FindByKeycloakID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func GetReviewModerationListHandler,"+
+				" method SelectListReviewByStatus", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
+func (h *HandlerProfile) ApproveReviewHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "ApproveReviewHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/approve")
+		return h.moderateReview(ctf, profile.ReviewStatusPublished)
+	}
+}
+
+func (h *HandlerProfile) RejectReviewHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "RejectReviewHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/review/reject")
+		return h.moderateReview(ctf, profile.ReviewStatusRejected)
+	}
+}
+
+// moderateReview sets the moderation status of the review chosen by a moderator
+func (h *HandlerProfile) moderateReview(ctf *fiber.Ctx, status string) error {
+	req := profile.RequestModerateReview{}
+	if err := ctf.BodyParser(&req); err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateReview, method BodyParser", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	reviewID, err := strconv.ParseUint(req.ID, 10, 64)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateReview, method ParseUint", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	reviewInDB, err := h.uc.FindReviewById(ctf.UserContext(), reviewID)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateReview, method FindReviewById", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusNotFound)
+	}
+	if reviewInDB.HasDeleted {
+		err := appError.NewNotFound("review has already been deleted")
+		return r.WrapError(ctf, err, http.StatusNotFound)
+	}
+	reviewDto := &profile.ReviewProfile{
+		ID:         reviewInDB.ID,
+		ProfileID:  reviewInDB.ProfileID,
+		HumanID:    reviewInDB.HumanID,
+		Message:    reviewInDB.Message,
+		Rating:     reviewInDB.Rating,
+		HasDeleted: reviewInDB.HasDeleted,
+		HasEdited:  reviewInDB.HasEdited,
+		Status:     status,
+		CreatedAt:  reviewInDB.CreatedAt,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	review, err := h.uc.UpdateReview(ctf.UserContext(), reviewDto)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Error("error func moderateReview, method UpdateReview", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusInternalServerError)
+	}
+	return r.WrapOk(ctf, review)
+}
+
+func (h *HandlerProfile) AddLikeHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddLikeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/like/add")
+		req := profile.RequestAddLike{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddLikeHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		if !isExistLike {
-			h.logger.Debug("error func DeleteLikeHandler, method !isExistLike by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			msg := errorDomain.ResponseError{
-				StatusCode: http.StatusNotFound,
-				Success:    false,
-				Message:    "not found",
-			}
-			return ctf.Status(http.StatusNotFound).JSON(msg)
+		humanID, err := strconv.ParseUint(req.HumanID, 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddLikeHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddLikeHandler, method FindByKeycloakID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddLikeHandler, method UpdateLastOnline", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		isBlocked, err := h.uc.CheckIfBlockExists(ctf.UserContext(), p.ID, humanID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddLikeHandler, method CheckIfBlockExists",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if isBlocked {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		isAllowed, err := h.reserveQuota(ctf, p, profile.QuotaActionLike)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddLikeHandler, method reserveQuota", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isAllowed {
+			err := errorDomain.NewCustomError(errors.New("like limit has been reached"),
+				http.StatusTooManyRequests)
+			return r.WrapError(ctf, err, http.StatusTooManyRequests)
+		}
+		likeDto := &profile.LikeProfile{
+			ProfileID: p.ID,
+			HumanID:   humanID,
+			IsLiked:   true,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		like, err := h.uc.AddLike(ctf.UserContext(), likeDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddLikeHandler, method AddLike", zap.Error(err))
+			h.releaseQuota(ctf, p, profile.QuotaActionLike)
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		h.countLike(ctf, p.ID, humanID)
+		return r.WrapCreated(ctf, like)
+	}
+}
+
+// countLike counts the like and the match when the human has liked the profile as well
+func (h *HandlerProfile) countLike(ctf *fiber.Ctx, profileID, humanID uint64) {
+	h.metrics.CountEvent(metrics.EventLike)
+	l, isExist, err := h.uc.FindLikeByHumanID(ctf.UserContext(), humanID, profileID)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Error("error func countLike, method FindLikeByHumanID", zap.Error(err))
+		return
+	}
+	if isExist && l.IsLiked {
+		h.metrics.CountEvent(metrics.EventMatch)
+	}
+}
+
+// reserveQuota counts the action against the daily quota of the profile, sets the quota headers and reports whether
+// the profile may perform the action. The reserved action is given back with releaseQuota when it fails.
+func (h *HandlerProfile) reserveQuota(ctf *fiber.Ctx, p *profile.Profile, action string) (bool, error) {
+	q, isReserved, err := h.uc.ReserveQuota(ctf.UserContext(), p, action)
+	if err != nil {
+		return false, err
+	}
+	if q.IsUnlimited {
+		return true, nil
+	}
+	ctf.Set("X-Quota-Limit", strconv.FormatUint(q.Limit, 10))
+	ctf.Set("X-Quota-Reset", strconv.FormatInt(q.ResetAt.Unix(), 10))
+	ctf.Set("X-Quota-Remaining", strconv.FormatUint(q.Remaining, 10))
+	if !isReserved {
+		retryAfter := uint64(math.Ceil(time.Until(q.ResetAt).Seconds()))
+		ctf.Set(fiber.HeaderRetryAfter, strconv.FormatUint(retryAfter, 10))
+		return false, nil
+	}
+	return true, nil
+}
+
+func (h *HandlerProfile) releaseQuota(ctf *fiber.Ctx, p *profile.Profile, action string) {
+	if err := h.uc.ReleaseQuota(ctf.UserContext(), p, action); err != nil {
+		h.logger.With(ctf.UserContext()).Error("error func releaseQuota, method ReleaseQuota", zap.Error(err))
+	}
+}
+
+func (h *HandlerProfile) DeleteLikeHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "DeleteLikeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/like/delete")
+		req := profile.RequestDeleteLike{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteLikeHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), l.ProfileID)
+		likeID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func DeleteLikeHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteLikeHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		l, isExistLike, err := h.uc.FindLikeByID(ctf.UserContext(), likeID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteLikeHandler, method FindByKeycloakID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isExistLike {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteLikeHandler, method !isExistLike", zap.Error(err))
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), l.ProfileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteLikeHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
 		likeDto := &profile.LikeProfile{
 			ID:        likeID,
 			ProfileID: l.ProfileID,
@@ -1620,11 +1798,10 @@ func (h *HandlerProfile) DeleteLikeHandler() fiber.Handler {
 			CreatedAt: l.CreatedAt,
 			UpdatedAt: time.Now().UTC(),
 		}
-		like, err := h.uc.DeleteLike(ctf.Context(), likeDto)
+		like, err := h.uc.DeleteLike(ctf.UserContext(), likeDto)
 		if err != nil {
-			h.logger.Debug("error func DeleteLikeHandler, method DeleteLike by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func DeleteLikeHandler, method DeleteLike", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, like)
 	}
@@ -1632,40 +1809,32 @@ func (h *HandlerProfile) DeleteLikeHandler() fiber.Handler {
 
 func (h *HandlerProfile) UpdateLikeHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/like/update")
+		defer h.begin(ctf, "UpdateLikeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/like/update")
 		req := profile.RequestUpdateLike{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateLikeHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateLikeHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		likeID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func UpdateLikeHandler, method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateLikeHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		l, isExist, err := h.uc.FindLikeByID(ctf.Context(), likeID)
+		l, isExist, err := h.uc.FindLikeByID(ctf.UserContext(), likeID)
 		if err != nil {
-			h.logger.Debug("error func UpdateLikeHandler, method FindLikeByID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateLikeHandler, method FindLikeByID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		if !isExist {
-			h.logger.Debug("error func UpdateLikeHandler, method !isExist by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			msg := errorDomain.ResponseError{
-				StatusCode: http.StatusNotFound,
-				Success:    false,
-				Message:    "not found",
-			}
-			return ctf.Status(http.StatusNotFound).JSON(msg)
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateLikeHandler, method !isExist", zap.Error(err))
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), l.ProfileID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), l.ProfileID)
 		if err != nil {
-			h.logger.Debug("error func UpdateLikeHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateLikeHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		likeDto := &profile.LikeProfile{
 			ID:        likeID,
@@ -1675,43 +1844,421 @@ func (h *HandlerProfile) UpdateLikeHandler() fiber.Handler {
 			CreatedAt: l.CreatedAt,
 			UpdatedAt: time.Now().UTC(),
 		}
-		like, err := h.uc.UpdateLike(ctf.Context(), likeDto)
+		like, err := h.uc.UpdateLike(ctf.UserContext(), likeDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateLikeHandler, method UpdateLike by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateLikeHandler, method UpdateLike", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
+		h.countLike(ctf, l.ProfileID, l.HumanID)
 		return r.WrapCreated(ctf, like)
 	}
 }
 
+func (h *HandlerProfile) GetIncomingLikeListHandler() fiber.Handler {
+	return h.getLikeListHandler("GET /api/v1/like/incoming", profile.LikeDirectionIncoming)
+}
+
+func (h *HandlerProfile) GetOutgoingLikeListHandler() fiber.Handler {
+	return h.getLikeListHandler("GET /api/v1/like/outgoing", profile.LikeDirectionOutgoing)
+}
+
+func (h *HandlerProfile) GetLikeHistoryHandler() fiber.Handler {
+	return h.getLikeListHandler("GET /api/v1/like/history", profile.LikeDirectionAll)
+}
+
+func (h *HandlerProfile) getLikeListHandler(route string, direction string) fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "getLikeListHandler")()
+		h.logger.With(ctf.UserContext()).Info(route)
+		params := profile.QueryParamsLikeList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func getLikeListHandler, method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		params.Direction = direction
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), params.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func getLikeListHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func getLikeListHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		response, err := h.uc.SelectListLike(ctf.UserContext(), p.ID, &params)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func getLikeListHandler, method SelectListLike",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// Only entitled profiles can see who liked them
+		if !h.uc.IsEntitled(p, profile.FeatureWhoLikedMe) {
+			for _, l := range response.Content {
+				if l.Direction == profile.LikeDirectionIncoming {
+					l.IsHidden = true
+					l.Human = nil
+				}
+			}
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
+func (h *HandlerProfile) AddSwipeHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddSwipeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/swipe/add")
+		req := profile.RequestAddSwipe{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSwipeHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if req.Action != profile.SwipeActionLike && req.Action != profile.SwipeActionPass &&
+			req.Action != profile.SwipeActionSuperLike {
+			err := fmt.Errorf("unknown swipe action: %s", req.Action)
+			h.logger.With(ctf.UserContext()).Warn("error func AddSwipeHandler, method Action", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		humanID, err := strconv.ParseUint(req.HumanID, 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSwipeHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method FindBySessionID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		isBlocked, err := h.uc.CheckIfBlockExists(ctf.UserContext(), p.ID, humanID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSwipeHandler, method CheckIfBlockExists",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if isBlocked {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		swipeDto := &profile.SwipeProfile{
+			ProfileID: p.ID,
+			HumanID:   humanID,
+			Action:    req.Action,
+			IsRewound: false,
+			ExpiresAt: nil,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		if req.Action == profile.SwipeActionPass {
+			expiresAt := time.Now().UTC().Add(profile.SwipePassTTL)
+			swipeDto.ExpiresAt = &expiresAt
+		} else {
+			// Like and super like are also stored as a like, so that they are shown to the liked profile
+			l, isExistLike, err := h.uc.FindLikeByHumanID(ctf.UserContext(), p.ID, humanID)
+			if err != nil {
+				h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method FindLikeByHumanID",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
+			}
+			if isExistLike {
+				likeDto := &profile.LikeProfile{
+					ID:        l.ID,
+					ProfileID: l.ProfileID,
+					HumanID:   l.HumanID,
+					IsLiked:   true,
+					CreatedAt: l.CreatedAt,
+					UpdatedAt: time.Now().UTC(),
+				}
+				_, err = h.uc.UpdateLike(ctf.UserContext(), likeDto)
+				if err != nil {
+					h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method UpdateLike",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
+				}
+			} else {
+				isAllowed, err := h.reserveQuota(ctf, p, profile.QuotaActionLike)
+				if err != nil {
+					h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method reserveQuota",
+						zap.Error(err))
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
+				}
+				if !isAllowed {
+					err := errorDomain.NewCustomError(errors.New("like limit has been reached"),
+						http.StatusTooManyRequests)
+					return r.WrapError(ctf, err, http.StatusTooManyRequests)
+				}
+				likeDto := &profile.LikeProfile{
+					ProfileID: p.ID,
+					HumanID:   humanID,
+					IsLiked:   true,
+					CreatedAt: time.Now().UTC(),
+					UpdatedAt: time.Now().UTC(),
+				}
+				_, err = h.uc.AddLike(ctf.UserContext(), likeDto)
+				if err != nil {
+					h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method AddLike", zap.Error(err))
+					h.releaseQuota(ctf, p, profile.QuotaActionLike)
+					return r.WrapError(ctf, err, http.StatusInternalServerError)
+				}
+			}
+			h.countLike(ctf, p.ID, humanID)
+		}
+		swipe, err := h.uc.AddSwipe(ctf.UserContext(), swipeDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSwipeHandler, method AddSwipe", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, swipe)
+	}
+}
+
+func (h *HandlerProfile) RewindSwipeHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "RewindSwipeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/swipe/rewind")
+		req := profile.RequestRewindSwipe{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RewindSwipeHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func RewindSwipeHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func RewindSwipeHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !h.uc.IsEntitled(p, profile.FeatureRewind) {
+			err := appError.NewForbidden("rewind is available only with premium")
+			return r.WrapError(ctf, err, http.StatusForbidden)
+		}
+		count, err := h.uc.CountRewindTodayByProfileID(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func RewindSwipeHandler,"+
+				" method CountRewindTodayByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if count >= profile.SwipeRewindLimit {
+			err := errorDomain.NewCustomError(errors.New("rewind limit has been reached"),
+				http.StatusTooManyRequests)
+			return r.WrapError(ctf, err, http.StatusTooManyRequests)
+		}
+		s, isExist, err := h.uc.FindLastPassSwipe(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func RewindSwipeHandler, method FindLastPassSwipe",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if !isExist {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		swipeDto := &profile.SwipeProfile{
+			ID:        s.ID,
+			ProfileID: s.ProfileID,
+			HumanID:   s.HumanID,
+			Action:    s.Action,
+			IsRewound: true,
+			ExpiresAt: s.ExpiresAt,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: time.Now().UTC(),
+		}
+		swipe, err := h.uc.UpdateSwipe(ctf.UserContext(), swipeDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func RewindSwipeHandler, method UpdateSwipe", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, swipe)
+	}
+}
+
+func (h *HandlerProfile) AddSubscriptionHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddSubscriptionHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/subscription/add")
+		req := profile.RequestAddSubscription{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSubscriptionHandler, method BodyParser",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if req.Plan != profile.SubscriptionPlanPremium {
+			err := fmt.Errorf("unknown subscription plan: %s", req.Plan)
+			h.logger.With(ctf.UserContext()).Warn("error func AddSubscriptionHandler, method Plan", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		profileID, err := strconv.ParseUint(req.ProfileID, 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSubscriptionHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindById(ctf.UserContext(), profileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSubscriptionHandler, method FindById", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		subscription, err := h.uc.ActivateSubscription(ctf.UserContext(), p.ID, req.Plan, req.Period)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSubscriptionHandler,"+
+				" method ActivateSubscription", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, subscription)
+	}
+}
+
+func (h *HandlerProfile) GetSubscriptionBySessionIDHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetSubscriptionBySessionIDHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/subscription/session/:id")
+		sessionID := ctf.Params("id")
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), sessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetSubscriptionBySessionIDHandler,"+
+				" method FindBySessionID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		subscription, isExist, err := h.uc.FindActiveSubscriptionByProfileID(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetSubscriptionBySessionIDHandler,"+
+				" method FindActiveSubscriptionByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if !isExist {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		return r.WrapOk(ctf, subscription)
+	}
+}
+
+func (h *HandlerProfile) AddPaymentHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddPaymentHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/payment/add")
+		req := profile.RequestAddPayment{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddPaymentHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddPaymentHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddPaymentHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		payment, err := h.uc.CreateInvoice(ctf.UserContext(), p.ID, req.Plan, req.Period)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddPaymentHandler, method CreateInvoice", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, payment)
+	}
+}
+
+func (h *HandlerProfile) GetVisitListHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetVisitListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/visit/list")
+		params := profile.QueryParamsVisitList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetVisitListHandler, method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), params.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetVisitListHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetVisitListHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		response, err := h.uc.SelectListVisit(ctf.UserContext(), p.ID, &params)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetVisitListHandler, method SelectListVisit",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
 func (h *HandlerProfile) AddBlockHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/block/add")
+		defer h.begin(ctf, "AddBlockHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/block/add")
 		req := profile.RequestAddBlock{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func AddBlockHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddBlockHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		blockedUserID, err := strconv.ParseUint(req.BlockedUserID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func AddBlockHandler, method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddBlockHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindBySessionID(ctf.Context(), req.SessionID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
 		if err != nil {
-			h.logger.Debug("error func AddBlockHandler, method FindBySessionID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddBlockHandler, method FindBySessionID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), p.ID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func AddBlockHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func AddBlockHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if blockedUserID == p.ID {
+			err := appError.NewValidation("profile can't block itself")
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
+		b, isExist, err := h.uc.FindBlockByHumanID(ctf.UserContext(), p.ID, blockedUserID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddBlockHandler, method FindBlockByHumanID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if isExist && b.IsBlocked {
+			err := appError.NewConflict("profile has already been blocked")
+			return r.WrapError(ctf, err, http.StatusConflict)
+		}
+		// A block is stored once by the blocker and applies to both profiles, a lifted block is renewed
+		if isExist {
+			blockDto := &profile.BlockedProfile{
+				ID:            b.ID,
+				ProfileID:     b.ProfileID,
+				BlockedUserID: b.BlockedUserID,
+				IsBlocked:     true,
+				CreatedAt:     b.CreatedAt,
+				UpdatedAt:     time.Now().UTC(),
+			}
+			block, err := h.uc.UpdateBlock(ctf.UserContext(), blockDto)
+			if err != nil {
+				h.logger.With(ctf.UserContext()).Error("error func AddBlockHandler, method UpdateBlock", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
+			}
+			return r.WrapCreated(ctf, block)
+		}
 		blockDto := &profile.BlockedProfile{
 			ProfileID:     p.ID,
 			BlockedUserID: blockedUserID,
@@ -1719,24 +2266,10 @@ func (h *HandlerProfile) AddBlockHandler() fiber.Handler {
 			CreatedAt:     time.Now().UTC(),
 			UpdatedAt:     time.Now().UTC(),
 		}
-		block, err := h.uc.AddBlock(ctf.Context(), blockDto)
+		block, err := h.uc.AddBlock(ctf.UserContext(), blockDto)
 		if err != nil {
-			h.logger.Debug("error func AddBlockHandler, method AddBlock by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
-		}
-		blockForBlockedUserDto := &profile.BlockedProfile{
-			ProfileID:     blockedUserID,
-			BlockedUserID: p.ID,
-			IsBlocked:     true,
-			CreatedAt:     time.Now().UTC(),
-			UpdatedAt:     time.Now().UTC(),
-		}
-		_, err = h.uc.AddBlock(ctf.Context(), blockForBlockedUserDto)
-		if err != nil {
-			h.logger.Debug("error func AddBlockHandler, method AddBlock by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddBlockHandler, method AddBlock", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, block)
 	}
@@ -1744,85 +2277,187 @@ func (h *HandlerProfile) AddBlockHandler() fiber.Handler {
 
 func (h *HandlerProfile) UpdateBlockHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/block/update")
+		defer h.begin(ctf, "UpdateBlockHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/block/update")
 		req := profile.RequestUpdateBlock{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func UpdateBlockHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateBlockHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateBlockHandler, method Validate", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		blockID, err := strconv.ParseUint(req.ID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func UpdateBlockHandler method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateBlockHandler method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		b, isExist, err := h.uc.FindBlockByID(ctf.Context(), blockID)
+		b, isExist, err := h.uc.FindBlockByID(ctf.UserContext(), blockID)
 		if err != nil {
-			h.logger.Debug("error func UpdateBlockHandler, method FindBlockByID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateBlockHandler, method FindBlockByID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		if !isExist {
-			h.logger.Debug("error func UpdateBlockHandler, method !isExist by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			msg := errorDomain.ResponseError{
-				StatusCode: http.StatusNotFound,
-				Success:    false,
-				Message:    "not found",
-			}
-			return ctf.Status(http.StatusNotFound).JSON(msg)
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateBlockHandler, method !isExist", zap.Error(err))
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), b.ProfileID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
 		if err != nil {
-			h.logger.Debug("error func UpdateBlockHandler, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateBlockHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// Only the blocker can lift the block
+		if b.ProfileID != p.ID {
+			err := appError.NewForbidden("block belongs to another profile")
+			return r.WrapError(ctf, err, http.StatusForbidden)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), b.ProfileID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func UpdateBlockHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		blockDto := &profile.BlockedProfile{
 			ID:            blockID,
 			ProfileID:     b.ProfileID,
-			BlockedUserID: blockID,
+			BlockedUserID: b.BlockedUserID,
 			IsBlocked:     false,
 			CreatedAt:     b.CreatedAt,
 			UpdatedAt:     time.Now().UTC(),
 		}
-		like, err := h.uc.UpdateBlock(ctf.Context(), blockDto)
+		like, err := h.uc.UpdateBlock(ctf.UserContext(), blockDto)
 		if err != nil {
-			h.logger.Debug("error func UpdateBlockHandler, method UpdateBlock by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func UpdateBlockHandler, method UpdateBlock", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
 		return r.WrapCreated(ctf, like)
 	}
 }
 
+func (h *HandlerProfile) DeleteBlockHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "DeleteBlockHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/block/delete")
+		req := profile.RequestDeleteBlock{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteBlockHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		blockedUserID, err := strconv.ParseUint(req.BlockedUserID, 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteBlockHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteBlockHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteBlockHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// Only the blocker can lift the block, the blocked profile has nothing to unblock
+		b, isExist, err := h.uc.FindBlockByHumanID(ctf.UserContext(), p.ID, blockedUserID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteBlockHandler, method FindBlockByHumanID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isExist || !b.IsBlocked {
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteBlockHandler, method !isExist", zap.Error(err))
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		blockDto := &profile.BlockedProfile{
+			ID:            b.ID,
+			ProfileID:     b.ProfileID,
+			BlockedUserID: b.BlockedUserID,
+			IsBlocked:     false,
+			CreatedAt:     b.CreatedAt,
+			UpdatedAt:     time.Now().UTC(),
+		}
+		block, err := h.uc.UpdateBlock(ctf.UserContext(), blockDto)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DeleteBlockHandler, method UpdateBlock", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, block)
+	}
+}
+
+func (h *HandlerProfile) GetBlockListHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetBlockListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/block/list")
+		params := profile.QueryParamsBlockList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetBlockListHandler, method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), params.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetBlockListHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetBlockListHandler, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		response, err := h.uc.SelectListBlock(ctf.UserContext(), p.ID, &params)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetBlockListHandler, method SelectListBlock",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
 func (h *HandlerProfile) AddComplaintHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
-		h.logger.Info("POST /api/v1/complaint/add")
+		defer h.begin(ctf, "AddComplaintHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/complaint/add")
 		req := profile.RequestAddComplaint{}
 		if err := ctf.BodyParser(&req); err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method BodyParser by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddComplaintHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		complaintUserId, err := strconv.ParseUint(req.ComplaintUserID, 10, 64)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method ParseUint by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func AddComplaintHandler, method ParseUint", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
-		p, err := h.uc.FindBySessionID(ctf.Context(), req.SessionID)
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method FindBySessionID by path "+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		err = h.uc.UpdateLastOnline(ctf.Context(), p.ID)
+		err = h.uc.UpdateLastOnline(ctf.UserContext(), p.ID)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandle, method UpdateLastOnline by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandle, method UpdateLastOnline",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		isAllowed, err := h.reserveQuota(ctf, p, profile.QuotaActionComplaint)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method reserveQuota",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isAllowed {
+			err := errorDomain.NewCustomError(errors.New("complaint limit has been reached"),
+				http.StatusTooManyRequests)
+			return r.WrapError(ctf, err, http.StatusTooManyRequests)
 		}
 		complaintDto := &profile.ComplaintProfile{
 			ProfileID:       p.ID,
@@ -1831,12 +2466,14 @@ func (h *HandlerProfile) AddComplaintHandler() fiber.Handler {
 			CreatedAt:       time.Now().UTC(),
 			UpdatedAt:       time.Now().UTC(),
 		}
-		complaint, err := h.uc.AddComplaint(ctf.Context(), complaintDto)
+		complaint, err := h.uc.AddComplaint(ctf.UserContext(), complaintDto)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method AddComplaint by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method AddComplaint",
+				zap.Error(err))
+			h.releaseQuota(ctf, p, profile.QuotaActionComplaint)
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
+		h.metrics.CountEvent(metrics.EventComplaint)
 		blockDto := &profile.BlockedProfile{
 			ProfileID:     p.ID,
 			BlockedUserID: complaintUserId,
@@ -1844,24 +2481,34 @@ func (h *HandlerProfile) AddComplaintHandler() fiber.Handler {
 			CreatedAt:     time.Now().UTC(),
 			UpdatedAt:     time.Now().UTC(),
 		}
-		_, err = h.uc.AddBlock(ctf.Context(), blockDto)
+		_, err = h.uc.AddBlock(ctf.UserContext(), blockDto)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method AddBlock by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method AddBlock", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
 		}
-		listComplaint, err := h.uc.SelectListComplaintByID(ctf.Context(), complaintUserId)
+		listComplaint, err := h.uc.SelectListComplaintByID(ctf.UserContext(), complaintUserId)
 		if err != nil {
-			h.logger.Debug("error func AddComplaintHandler, method SelectListComplaintByID by path"+
-				" internal/handler/profile/profile.go", zap.Error(err))
-			return r.WrapError(ctf, err, http.StatusBadRequest)
+			h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler,"+
+				" method SelectListComplaintByID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// A single complaint can't hide the profile, the age check is requested when enough profiles complained
+		if req.Reason == profile.ComplaintReasonUnderage &&
+			countUnderageReporters(listComplaint) >= profile.UnderageComplaintLimit {
+			_, err := h.uc.RequestAgeVerification(
+				ctf.UserContext(), complaintUserId, profile.AgeVerificationReasonComplaint)
+			if err != nil {
+				h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler,"+
+					" method RequestAgeVerification", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
+			}
 		}
 		if len(filterComplaintsByCurrentMonth(listComplaint)) > 1 {
-			p, err := h.uc.FindById(ctf.Context(), complaintUserId)
+			p, err := h.uc.FindById(ctf.UserContext(), complaintUserId)
 			if err != nil {
-				h.logger.Debug("error func AddComplaintHandler, method FindById by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method FindById",
+					zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
 			profileDto := &profile.Profile{
 				ID:             p.ID,
@@ -1882,12 +2529,12 @@ func (h *HandlerProfile) AddComplaintHandler() fiber.Handler {
 				UpdatedAt:      p.UpdatedAt,
 				LastOnline:     p.LastOnline,
 			}
-			_, err = h.uc.Update(ctf.Context(), profileDto)
+			_, err = h.uc.Update(ctf.UserContext(), profileDto)
 			if err != nil {
-				h.logger.Debug("error func AddComplaintHandler, method Update by path"+
-					" internal/handler/profile/profile.go", zap.Error(err))
-				return r.WrapError(ctf, err, http.StatusBadRequest)
+				h.logger.With(ctf.UserContext()).Error("error func AddComplaintHandler, method Update", zap.Error(err))
+				return r.WrapError(ctf, err, http.StatusInternalServerError)
 			}
+			h.metrics.CountEvent(metrics.EventAutoBlock)
 		}
 		return r.WrapCreated(ctf, complaint)
 	}
@@ -1906,9 +2553,363 @@ func filterComplaintsByCurrentMonth(complaints []*profile.ComplaintProfile) []*p
 	return filteredComplaints
 }
 
+// countUnderageReporters returns the number of the distinct profiles which complained about the underage owner
+func countUnderageReporters(complaints []*profile.ComplaintProfile) int {
+	reporters := make(map[uint64]struct{})
+	for _, complaint := range complaints {
+		if complaint.Reason == profile.ComplaintReasonUnderage {
+			reporters[complaint.ProfileID] = struct{}{}
+		}
+	}
+	return len(reporters)
+}
+
+func (h *HandlerProfile) GetAgeVerificationListHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetAgeVerificationListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/age/moderation/list")
+		params := profile.QueryParamsAgeVerificationList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetAgeVerificationListHandler,"+
+				" method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if params.Status == "" {
+			params.Status = profile.AgeVerificationStatusPending
+		}
+		response, err := h.uc.SelectListAgeVerificationByStatus(ctf.UserContext(), &params)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetAgeVerificationListHandler,"+
+				" method SelectListAgeVerificationByStatus", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
+func (h *HandlerProfile) ConfirmAgeVerificationHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "ConfirmAgeVerificationHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/age/confirm")
+		return h.moderateAgeVerification(ctf, profile.AgeVerificationStatusConfirmed)
+	}
+}
+
+func (h *HandlerProfile) RejectAgeVerificationHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "RejectAgeVerificationHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/age/reject")
+		return h.moderateAgeVerification(ctf, profile.AgeVerificationStatusRejected)
+	}
+}
+
+// moderateAgeVerification sets the status of the age check chosen by a moderator
+func (h *HandlerProfile) moderateAgeVerification(ctf *fiber.Ctx, status string) error {
+	req := profile.RequestModerateAgeVerification{}
+	if err := ctf.BodyParser(&req); err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateAgeVerification, method BodyParser", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	if err := validation.Validate(&req); err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateAgeVerification, method Validate", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	verification, err := h.uc.ModerateAgeVerification(ctf.UserContext(), req.ID, status)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateAgeVerification,"+
+			" method ModerateAgeVerification", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusInternalServerError)
+	}
+	return r.WrapOk(ctf, verification)
+}
+
+func (h *HandlerProfile) AddSelfieChallengeHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddSelfieChallengeHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/verification/challenge")
+		req := profile.RequestAddSelfieChallenge{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieChallengeHandler, method BodyParser",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieChallengeHandler, method Validate",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSelfieChallengeHandler,"+
+				" method FindBySessionID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		challenge, err := h.uc.AddSelfieChallenge(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieChallengeHandler,"+
+				" method AddSelfieChallenge", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, challenge)
+	}
+}
+
+func (h *HandlerProfile) AddSelfieHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddSelfieHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/verification/selfie")
+		req := profile.RequestAddSelfie{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieHandler, method Validate", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		file, err := ctf.FormFile("image")
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieHandler, method FormFile", zap.Error(err))
+			err := appError.NewValidation("invalid request",
+				&appError.FieldError{Field: "image", Message: "is required"})
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if !strings.HasPrefix(file.Header.Get("Content-Type"), "image/") {
+			err := appError.NewValidation("invalid request",
+				&appError.FieldError{Field: "image", Message: "must be an image"})
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSelfieHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		challenge, err := h.uc.FindSelfieChallenge(ctf.UserContext(), p.ID, req.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddSelfieHandler, method FindSelfieChallenge",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		directoryPath := filepath.Join(h.selfieDirectory, strconv.FormatUint(p.ID, 10))
+		if err := os.MkdirAll(directoryPath, 0700); err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSelfieHandler, method MkdirAll", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		filePath := filepath.Join(directoryPath, fmt.Sprintf("%d%s", challenge.ID, filepath.Ext(file.Filename)))
+		if err := ctf.SaveFile(file, filePath); err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSelfieHandler, method SaveFile", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		verification, err := h.uc.SubmitSelfie(ctf.UserContext(), challenge, filePath)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddSelfieHandler, method SubmitSelfie", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, verification)
+	}
+}
+
+func (h *HandlerProfile) GetSelfieModerationListHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetSelfieModerationListHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/verification/moderation/list")
+		params := profile.QueryParamsSelfieModerationList{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetSelfieModerationListHandler,"+
+				" method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if params.Status == "" {
+			params.Status = profile.SelfieStatusPending
+		}
+		response, err := h.uc.SelectListSelfieVerificationByStatus(ctf.UserContext(), &params)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetSelfieModerationListHandler,"+
+				" method SelectListSelfieVerificationByStatus", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
+func (h *HandlerProfile) GetSelfieHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetSelfieHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/profile/verification/selfie/:id")
+		id, err := strconv.ParseUint(ctf.Params("id"), 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetSelfieHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		verification, isExist, err := h.uc.FindSelfieVerificationByID(ctf.UserContext(), id)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetSelfieHandler,"+
+				" method FindSelfieVerificationByID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isExist || verification.FilePath == "" {
+			return r.WrapError(ctf, appError.NewNotFound("selfie not found"), http.StatusNotFound)
+		}
+		return ctf.SendFile(verification.FilePath)
+	}
+}
+
+func (h *HandlerProfile) ApproveSelfieHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "ApproveSelfieHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/verification/approve")
+		return h.moderateSelfie(ctf, profile.SelfieStatusApproved)
+	}
+}
+
+func (h *HandlerProfile) RejectSelfieHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "RejectSelfieHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/profile/verification/reject")
+		return h.moderateSelfie(ctf, profile.SelfieStatusRejected)
+	}
+}
+
+// moderateSelfie sets the status of the selfie chosen by a moderator
+func (h *HandlerProfile) moderateSelfie(ctf *fiber.Ctx, status string) error {
+	req := profile.RequestModerateSelfie{}
+	if err := ctf.BodyParser(&req); err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateSelfie, method BodyParser", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	if err := validation.Validate(&req); err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateSelfie, method Validate", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusBadRequest)
+	}
+	verification, err := h.uc.ModerateSelfie(ctf.UserContext(), req.ID, status)
+	if err != nil {
+		h.logger.With(ctf.UserContext()).Warn("error func moderateSelfie, method ModerateSelfie", zap.Error(err))
+		return r.WrapError(ctf, err, http.StatusInternalServerError)
+	}
+	return r.WrapOk(ctf, verification)
+}
+
 func replaceExtension(filename string) string {
 	// Удаляем текущее расширение
 	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
 	// Добавляем новое расширение .webp
 	return filename + ".webp"
 }
+
+func (h *HandlerProfile) AddExportHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "AddExportHandler")()
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/export/add")
+		req := profile.RequestAddExport{}
+		if err := ctf.BodyParser(&req); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func AddExportHandler, method BodyParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), req.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddExportHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		_, isExist, err := h.uc.FindPendingExportByProfileID(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddExportHandler,"+
+				" method FindPendingExportByProfileID", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if isExist {
+			err := appError.NewConflict("export is already being prepared")
+			return r.WrapError(ctf, err, http.StatusConflict)
+		}
+		export, err := h.uc.AddExport(ctf.UserContext(), p.ID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func AddExportHandler, method AddExport", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		return r.WrapCreated(ctf, export)
+	}
+}
+
+// GetExportByIDHandler returns the export of the profile with the download url when the archive is ready, so the
+// archive can be downloaded without the telegram notification
+func (h *HandlerProfile) GetExportByIDHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "GetExportByIDHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/export/detail/:id")
+		id, err := strconv.ParseUint(ctf.Params("id"), 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetExportByIDHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		params := profile.QueryParamsExportDetail{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetExportByIDHandler, method QueryParser", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		if err := validation.Validate(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func GetExportByIDHandler, method Validate", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		p, err := h.uc.FindBySessionID(ctf.UserContext(), params.SessionID)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetExportByIDHandler, method FindBySessionID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		export, isExist, err := h.uc.FindExportByID(ctf.UserContext(), id)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func GetExportByIDHandler, method FindExportByID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		// The exports of the other profiles are not revealed
+		if !isExist || export.ProfileID != p.ID {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		response := &profile.ResponseExportProfile{ExportProfile: export}
+		if export.Status == profile.ExportStatusReady {
+			downloadURL := profile.GetExportDownloadURL(h.exportDownloadURL, export)
+			response.DownloadURL = &downloadURL
+		}
+		return r.WrapOk(ctf, response)
+	}
+}
+
+func (h *HandlerProfile) DownloadExportHandler() fiber.Handler {
+	return func(ctf *fiber.Ctx) error {
+		defer h.begin(ctf, "DownloadExportHandler")()
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/export/download/:id")
+		id, err := strconv.ParseUint(ctf.Params("id"), 10, 64)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DownloadExportHandler, method ParseUint", zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		params := profile.QueryParamsExportDownload{}
+		if err := ctf.QueryParser(&params); err != nil {
+			h.logger.With(ctf.UserContext()).Warn("error func DownloadExportHandler, method QueryParser",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusBadRequest)
+		}
+		export, isExist, err := h.uc.FindExportByID(ctf.UserContext(), id)
+		if err != nil {
+			h.logger.With(ctf.UserContext()).Error("error func DownloadExportHandler, method FindExportByID",
+				zap.Error(err))
+			return r.WrapError(ctf, err, http.StatusInternalServerError)
+		}
+		if !isExist || subtle.ConstantTimeCompare([]byte(export.Token), []byte(params.Token)) != 1 {
+			return r.WrapError(ctf, appError.NewNotFound("not found"), http.StatusNotFound)
+		}
+		// The archive may still be on the disk until the expiry job has run, the link stops working at ExpiresAt
+		if export.Status == profile.ExportStatusExpired ||
+			(export.ExpiresAt != nil && time.Now().UTC().After(*export.ExpiresAt)) {
+			err := errorDomain.NewCustomError(errors.New("export has expired"), http.StatusGone)
+			return r.WrapError(ctf, err, http.StatusGone)
+		}
+		if export.Status != profile.ExportStatusReady {
+			err := appError.NewConflict(fmt.Sprintf("export is %s", export.Status))
+			return r.WrapError(ctf, err, http.StatusConflict)
+		}
+		return ctf.Download(export.FilePath, "profile-export.zip")
+	}
+}
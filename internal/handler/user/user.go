@@ -22,18 +22,16 @@ func NewHandlerUser(l logger.Logger, uc *userUseCase.UseCaseUser) *HandlerUser {
 func (h *HandlerUser) PostRegisterHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
 		var ctx = ctf.UserContext()
-		h.logger.Info("POST /api/v1/user/register")
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/user/register")
 		var request = user.RegisterRequest{}
 		err := ctf.BodyParser(&request)
 		if err != nil {
-			h.logger.Debug("error func PostRegisterHandler, method BodyParser by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func PostRegisterHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		response, err := h.uc.Register(ctx, request)
 		if err != nil {
-			h.logger.Debug("error func PostRegisterHandler, method Register by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func PostRegisterHandler, method Register", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		return r.WrapCreated(ctf, response)
@@ -43,18 +41,16 @@ func (h *HandlerUser) PostRegisterHandler() fiber.Handler {
 func (h *HandlerUser) UpdateUserHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
 		var ctx = ctf.UserContext()
-		h.logger.Info("POST /api/v1/user/update")
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/user/update")
 		var request = user.RequestUpdateUser{}
 		err := ctf.BodyParser(&request)
 		if err != nil {
-			h.logger.Debug("error func UpdateUserHandle, method BodyParser by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func UpdateUserHandle, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		response, err := h.uc.UpdateUser(ctx, request)
 		if err != nil {
-			h.logger.Debug("error func UpdateUserHandle, method UpdateUser by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func UpdateUserHandle, method UpdateUser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		return r.WrapCreated(ctf, response)
@@ -64,18 +60,16 @@ func (h *HandlerUser) UpdateUserHandler() fiber.Handler {
 func (h *HandlerUser) DeleteUserHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
 		var ctx = ctf.UserContext()
-		h.logger.Info("POST /api/v1/user/delete")
+		h.logger.With(ctf.UserContext()).Info("POST /api/v1/user/delete")
 		var request = user.RequestDeleteUser{}
 		err := ctf.BodyParser(&request)
 		if err != nil {
-			h.logger.Debug("error func DeleteUserHandler, method BodyParser by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func DeleteUserHandler, method BodyParser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		err = h.uc.DeleteUser(ctx, request)
 		if err != nil {
-			h.logger.Debug("error func DeleteUserHandler, method DeleteUser by path internal/handler/user/user.go",
-				zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func DeleteUserHandler, method DeleteUser", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		return r.WrapCreated(ctf, nil)
@@ -85,17 +79,15 @@ func (h *HandlerUser) DeleteUserHandler() fiber.Handler {
 func (h *HandlerUser) GetUserListHandler() fiber.Handler {
 	return func(ctf *fiber.Ctx) error {
 		var ctx = ctf.UserContext()
-		h.logger.Info("GET /api/v1/user/list")
+		h.logger.With(ctf.UserContext()).Info("GET /api/v1/user/list")
 		query := user.QueryParamsUserList{}
 		if err := ctf.QueryParser(&query); err != nil {
-			h.logger.Debug("error func GetUserListHandler, method QueryParser by path"+
-				" internal/handler/user/user.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Warn("error func GetUserListHandler, method QueryParser", zap.Error(err))
 			return err
 		}
 		response, err := h.uc.GetUserList(ctx, query)
 		if err != nil {
-			h.logger.Debug("error func GetUserListHandler, method GetUserList by path"+
-				" internal/handler/user/user.go", zap.Error(err))
+			h.logger.With(ctf.UserContext()).Error("error func GetUserListHandler, method GetUserList", zap.Error(err))
 			return r.WrapError(ctf, err, http.StatusBadRequest)
 		}
 		return r.WrapOk(ctf, response)
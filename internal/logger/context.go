@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+	"github.com/EvgeniyBudaev/love-server/internal/shared/enums"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+type requestFieldsKey struct{}
+
+// requestFields keeps the fields of the request which are known only after the context is created
+type requestFields struct {
+	mu        sync.Mutex
+	route     string
+	profileID uint64
+}
+
+// NewContext returns the context of the request, its route and profile id are set later by SetRoute and
+// SetProfileID
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestFieldsKey{}, &requestFields{})
+}
+
+// SetRoute sets the route pattern of the request, it does nothing when the context is not a request context
+func SetRoute(ctx context.Context, route string) {
+	if f, ok := ctx.Value(requestFieldsKey{}).(*requestFields); ok {
+		f.mu.Lock()
+		f.route = route
+		f.mu.Unlock()
+	}
+}
+
+// SetProfileID sets the profile of the session which made the request
+func SetProfileID(ctx context.Context, profileID uint64) {
+	if f, ok := ctx.Value(requestFieldsKey{}).(*requestFields); ok {
+		f.mu.Lock()
+		f.profileID = profileID
+		f.mu.Unlock()
+	}
+}
+
+// contextLogger reads the fields of the context on every entry, so the fields which are set after With are logged
+type contextLogger struct {
+	logger *zap.Logger
+	ctx    context.Context
+}
+
+func (l *contextLogger) Debug(msg string, fields ...zapcore.Field) {
+	l.logger.Debug(msg, l.fields(fields)...)
+}
+
+func (l *contextLogger) Info(msg string, fields ...zapcore.Field) {
+	l.logger.Info(msg, l.fields(fields)...)
+}
+
+func (l *contextLogger) Warn(msg string, fields ...zapcore.Field) {
+	l.logger.Warn(msg, l.fields(fields)...)
+}
+
+func (l *contextLogger) Error(msg string, fields ...zapcore.Field) {
+	l.logger.Error(msg, l.fields(fields)...)
+}
+
+func (l *contextLogger) Fatal(msg string, fields ...zapcore.Field) {
+	l.logger.Fatal(msg, l.fields(fields)...)
+}
+
+func (l *contextLogger) With(ctx context.Context) Logger {
+	return &contextLogger{logger: l.logger, ctx: ctx}
+}
+
+func (l *contextLogger) fields(fields []zapcore.Field) []zapcore.Field {
+	result := make([]zapcore.Field, 0, len(fields)+4)
+	if requestId, ok := l.ctx.Value(enums.ContextKeyRequestId).(string); ok {
+		result = append(result, zap.String("requestId", requestId))
+	}
+	if sc := trace.SpanContextFromContext(l.ctx); sc.HasTraceID() {
+		result = append(result, zap.String("traceId", sc.TraceID().String()))
+	}
+	if f, ok := l.ctx.Value(requestFieldsKey{}).(*requestFields); ok {
+		f.mu.Lock()
+		if f.route != "" {
+			result = append(result, zap.String("route", f.route))
+		}
+		if f.profileID != 0 {
+			result = append(result, zap.Uint64("profileId", f.profileID))
+		}
+		f.mu.Unlock()
+	}
+	return append(result, fields...)
+}
@@ -1,29 +1,65 @@
 package logger
 
 import (
+	"context"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
 )
 
 type Logger interface {
 	Debug(string, ...zapcore.Field)
 	Info(string, ...zapcore.Field)
+	Warn(string, ...zapcore.Field)
 	Error(string, ...zapcore.Field)
 	Fatal(string, ...zapcore.Field)
+	// With returns the logger which adds the request id, the trace id, the profile id and the route of the context
+	With(ctx context.Context) Logger
+}
+
+// Sampling keeps the first Initial entries with the same level and message every second and then every Thereafter
+// entry, the sampling is off when Initial is 0
+type Sampling struct {
+	Initial    int
+	Thereafter int
+}
+
+type zapLogger struct {
+	*zap.Logger
 }
 
-func NewLogger(level string) (Logger, error) {
+// NewLogger builds the development console logger or, with FormatJSON, the production JSON logger
+func NewLogger(level, format string, sampling Sampling) (Logger, error) {
 	lvl, err := zap.ParseAtomicLevel(level)
 	if err != nil {
 		return nil, err
 	}
 	cfg := zap.NewDevelopmentConfig()
+	if format == FormatJSON {
+		cfg = zap.NewProductionConfig()
+		cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	}
 	cfg.Level = lvl
+	cfg.Sampling = nil
 	zl, err := cfg.Build()
 	if err != nil {
 		return nil, err
 	}
-	return zl, nil
+	if sampling.Initial > 0 {
+		zl = zl.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
+		}))
+	}
+	return &zapLogger{Logger: zl}, nil
+}
+
+func (l *zapLogger) With(ctx context.Context) Logger {
+	return &contextLogger{logger: l.Logger.WithOptions(zap.AddCallerSkip(1)), ctx: ctx}
 }
 
 func GetDefaultLevel() string {
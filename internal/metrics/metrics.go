@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "love"
+
+// The domain events which are counted by CountEvent
+const (
+	EventProfileCreated = "profile_created"
+	EventLike           = "like"
+	EventMatch          = "match"
+	EventComplaint      = "complaint"
+	EventAutoBlock      = "auto_block"
+	EventImageUpload    = "image_upload"
+)
+
+// Metrics keeps the collectors of the service in its own registry. The methods do nothing on a nil Metrics,
+// so the components may be used without metrics.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	events          *prometheus.CounterVec
+}
+
+// NewMetrics registers the HTTP, query and domain event collectors, the go runtime and process collectors and
+// the pool stats of the database
+func NewMetrics(db *sql.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of the HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of the repository methods.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "domain_events_total",
+			Help:      "Number of the domain events by event.",
+		}, []string{"event"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.events,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+	}
+	return m
+}
+
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveQuery observes the duration of the repository method since startedAt, it is meant to be deferred
+func (m *Metrics) ObserveQuery(repository, method string, startedAt time.Time) {
+	if m == nil {
+		return
+	}
+	m.queryDuration.WithLabelValues(repository, method).Observe(time.Since(startedAt).Seconds())
+}
+
+func (m *Metrics) CountEvent(event string) {
+	if m == nil {
+		return
+	}
+	m.events.WithLabelValues(event).Inc()
+}
+
+// Handler returns the handler which exposes the registry in the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// NewAccessLogMiddleware logs every request with its status and duration, the server errors are logged as errors
+func NewAccessLogMiddleware(l logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		startedAt := time.Now()
+		err := c.Next()
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+		ctx := c.UserContext()
+		logger.SetRoute(ctx, c.Route().Path)
+		fields := []zap.Field{
+			zap.String("method", strings.Clone(c.Method())),
+			zap.String("path", strings.Clone(c.Path())),
+			zap.Int("status", status),
+			zap.Int64("durationMs", time.Since(startedAt).Milliseconds()),
+			zap.String("ip", c.IP()),
+		}
+		if status >= fiber.StatusInternalServerError {
+			l.With(ctx).Error("request", append(fields, zap.Error(err))...)
+		} else {
+			l.With(ctx).Info("request", fields...)
+		}
+		return err
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/openapi"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// NewContractMiddleware checks the JSON responses of the routes against the OpenAPI document and logs the
+// violations, the response is sent unchanged. It is meant for the development and the staging environments.
+func NewContractMiddleware(d *openapi.Document, l logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err != nil || !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return err
+		}
+		violations := d.ValidateResponse(c.Method(), c.Route().Path, c.Response().StatusCode(), c.Response().Body())
+		if len(violations) > 0 {
+			l.With(c.UserContext()).Warn("response does not match the OpenAPI document",
+				zap.String("method", strings.Clone(c.Method())),
+				zap.String("route", c.Route().Path),
+				zap.Int("status", c.Response().StatusCode()),
+				zap.Strings("violations", violations))
+		}
+		return nil
+	}
+}
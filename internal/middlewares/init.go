@@ -18,21 +18,25 @@ func InitFiberMiddlewares(app *fiber.App,
 	grp fiber.Router,
 	imh *user.HandlerUser,
 	ph *profile.HandlerProfile,
+	sr SessionResolver,
 	initPublicRoutes func(grp fiber.Router, imh *user.HandlerUser, ph *profile.HandlerProfile),
-	initProtectedRoutes func(grp fiber.Router, ph *profile.HandlerProfile)) {
+	initProtectedRoutes func(grp fiber.Router, l logger.Logger, ph *profile.HandlerProfile)) {
 	app.Use(requestid.New())
 	app.Use(func(c *fiber.Ctx) error {
 		// get the request id that was added by requestid middleware
 		var requestId = c.Locals("requestid")
 		// create a new context and add the requestid to it
-		var ctx = context.WithValue(context.Background(), enums.ContextKeyRequestId, requestId)
+		var ctx = context.WithValue(logger.NewContext(context.Background()), enums.ContextKeyRequestId, requestId)
 		c.SetUserContext(ctx)
 		return c.Next()
 	})
+	app.Use(NewTracingMiddleware())
+	app.Use(NewAccessLogMiddleware(l))
+	app.Use(NewRateLimitMiddleware(cfg, l, sr))
 	// routes that don't require a JWT token
 	initPublicRoutes(grp, imh, ph)
 	tokenRetrospector := identity.NewIdentity(cfg, l)
 	app.Use(NewJwtMiddleware(cfg, tokenRetrospector, l))
 	// routes that require authentication/authorization
-	initProtectedRoutes(grp, ph)
+	initProtectedRoutes(grp, l, ph)
 }
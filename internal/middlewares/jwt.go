@@ -26,7 +26,7 @@ func NewJwtMiddleware(config *config.Config, tokenRetrospector TokenRetrospector
 	base64Str := config.RealmRS256PublicKey
 	publicKey, err := parseKeycloakRSAPublicKey(base64Str, logger)
 	if err != nil {
-		logger.Debug("error while NewJwtMiddleware. Error in parseKeycloakRSAPublicKey", zap.Error(err))
+		logger.Error("error while NewJwtMiddleware. Error in parseKeycloakRSAPublicKey", zap.Error(err))
 		panic(err)
 	}
 	return contribJwt.New(contribJwt.Config{
@@ -48,12 +48,12 @@ func successHandler(c *fiber.Ctx, tokenRetrospector TokenRetrospector, logger lo
 	c.SetUserContext(contextWithClaims)
 	rptResult, err := tokenRetrospector.RetrospectToken(ctx, jwtToken.Raw)
 	if err != nil {
-		logger.Debug("error while successHandler. Error in RetrospectToken", zap.Error(err))
+		logger.With(ctx).Error("error while successHandler. Error in RetrospectToken", zap.Error(err))
 		return err
 	}
 	if !*rptResult.Active {
 		err := fmt.Errorf("token is not active")
-		logger.Debug("error while successHandler. Error in parseKeycloakRSAPublicKey", zap.Error(err))
+		logger.With(ctx).Warn("error while successHandler. Error in parseKeycloakRSAPublicKey", zap.Error(err))
 		return r.WrapError(c, err, http.StatusUnauthorized)
 	}
 	return c.Next()
@@ -62,12 +62,12 @@ func successHandler(c *fiber.Ctx, tokenRetrospector TokenRetrospector, logger lo
 func parseKeycloakRSAPublicKey(base64Str string, logger logger.Logger) (*rsa.PublicKey, error) {
 	buf, err := base64.StdEncoding.DecodeString(base64Str)
 	if err != nil {
-		logger.Debug("error while parseKeycloakRSAPublicKey. Error in DecodeString", zap.Error(err))
+		logger.Error("error while parseKeycloakRSAPublicKey. Error in DecodeString", zap.Error(err))
 		return nil, err
 	}
 	parsedKey, err := x509.ParsePKIXPublicKey(buf)
 	if err != nil {
-		logger.Debug("error while parseKeycloakRSAPublicKey. Error in ParsePKIXPublicKey", zap.Error(err))
+		logger.Error("error while parseKeycloakRSAPublicKey. Error in ParsePKIXPublicKey", zap.Error(err))
 		return nil, err
 	}
 	publicKey, ok := parsedKey.(*rsa.PublicKey)
@@ -75,6 +75,6 @@ func parseKeycloakRSAPublicKey(base64Str string, logger logger.Logger) (*rsa.Pub
 		return publicKey, nil
 	}
 	err = fmt.Errorf("unexpected key type %T", publicKey)
-	logger.Debug("error while parseKeycloakRSAPublicKey", zap.Error(err))
+	logger.Error("error while parseKeycloakRSAPublicKey", zap.Error(err))
 	return nil, err
 }
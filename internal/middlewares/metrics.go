@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/metrics"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+	"time"
+)
+
+// routeUnmatched labels the requests which match no route, so that unknown paths do not create new series
+const routeUnmatched = "unmatched"
+
+// NewMetricsMiddleware observes the count and the duration of the requests by the route pattern
+func NewMetricsMiddleware(m *metrics.Metrics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		startedAt := time.Now()
+		err := c.Next()
+		status := c.Response().StatusCode()
+		route := c.Route().Path
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+			// The router returns 404 or 405 when no route matches, the handlers answer with JSON instead
+			if status == fiber.StatusNotFound || status == fiber.StatusMethodNotAllowed {
+				route = routeUnmatched
+			}
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+		// The method is copied, because fiber reuses its buffer after the request
+		m.ObserveRequest(strings.Clone(c.Method()), route, status, time.Since(startedAt))
+		return err
+	}
+}
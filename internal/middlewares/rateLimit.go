@@ -0,0 +1,88 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/config"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	errorDomain "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/error"
+	r "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/response"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRateLimitMax        = 120
+	defaultRateLimitExpiration = time.Minute
+)
+
+// SessionResolver finds the profile of the session of a request
+type SessionResolver interface {
+	FindBySessionID(ctx context.Context, sessionID string) (*profile.Profile, error)
+}
+
+// NewRateLimitMiddleware limits the number of requests per profile, requests without a known session are limited
+// per IP
+func NewRateLimitMiddleware(cfg *config.Config, logger logger.Logger, sr SessionResolver) fiber.Handler {
+	max := cfg.RateLimitMax
+	if max <= 0 {
+		max = defaultRateLimitMax
+	}
+	expiration := cfg.RateLimitExpiration
+	if expiration <= 0 {
+		expiration = defaultRateLimitExpiration
+	}
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: expiration,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return rateLimitKey(c, sr)
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			err := fmt.Errorf("too many requests")
+			logger.With(c.UserContext()).Warn("error while NewRateLimitMiddleware. Error in LimitReached",
+				zap.Error(err))
+			c.Set("X-RateLimit-Limit", strconv.Itoa(max))
+			c.Set("X-RateLimit-Remaining", "0")
+			return r.WrapError(c, errorDomain.NewCustomError(err, http.StatusTooManyRequests),
+				http.StatusTooManyRequests)
+		},
+	})
+}
+
+// rateLimitKey keys the request on the profile of its session. The session id is sent by the client, so it is
+// resolved to a profile first, otherwise any made up session id would get a bucket of its own
+func rateLimitKey(c *fiber.Ctx, sr SessionResolver) string {
+	sessionID := getSessionID(c)
+	if sessionID == "" {
+		return "ip:" + c.IP()
+	}
+	p, err := sr.FindBySessionID(c.UserContext(), sessionID)
+	if err != nil {
+		return "ip:" + c.IP()
+	}
+	return "profile:" + strconv.FormatUint(p.ID, 10)
+}
+
+// getSessionID returns the session id from the query, the JSON body or the form
+func getSessionID(c *fiber.Ctx) string {
+	if sessionID := c.Query("sessionId"); sessionID != "" {
+		return sessionID
+	}
+	if c.Is("json") {
+		body := struct {
+			SessionID string `json:"sessionId"`
+		}{}
+		if err := json.Unmarshal(c.Body(), &body); err == nil {
+			return body.SessionID
+		}
+		return ""
+	}
+	return c.FormValue("sessionId")
+}
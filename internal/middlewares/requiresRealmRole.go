@@ -19,7 +19,7 @@ func NewRequiresRealmRole(role string, logger logger.Logger) fiber.Handler {
 		jwtHelper := jwt.NewJwtHelper(claims)
 		if !jwtHelper.IsUserInRealmRole(role) {
 			err := fmt.Errorf("role authorization failed")
-			logger.Debug("error while NewRequiresRealmRole. Error in IsUserInRealmRole", zap.Error(err))
+			logger.With(ctx).Warn("error while NewRequiresRealmRole. Error in IsUserInRealmRole", zap.Error(err))
 			return r.WrapError(c, err, http.StatusUnauthorized)
 		}
 		return c.Next()
@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+// Document is the OpenAPI document of the api, only the parts which are used by the routes are modelled
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       *Info               `json:"info"`
+	Servers    []*Server           `json:"servers"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem is the operations of the path by the lower case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+const (
+	contentJSON      = "application/json"
+	contentMultipart = "multipart/form-data"
+	securityBearer   = "bearerAuth"
+	// defaultResponse is the key of the error response, every error is written by response.WrapError
+	defaultResponse = "default"
+)
+
+// NewDocument builds the document of the routes, serverURL is the prefix of the routes
+func NewDocument(title string, version string, serverURL string) *Document {
+	schemas := newSchemaRegistry()
+	d := &Document{
+		OpenAPI: Version,
+		Info:    &Info{Title: title, Version: version},
+		Servers: []*Server{{URL: serverURL}},
+		Paths:   make(map[string]PathItem),
+		Components: &Components{
+			Schemas: schemas.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				securityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	errorSchema := schemas.response(errorType)
+	for _, rt := range routes {
+		path := toOpenAPIPath(rt.path)
+		if d.Paths[path] == nil {
+			d.Paths[path] = make(PathItem)
+		}
+		d.Paths[path][strings.ToLower(rt.method)] = rt.operation(schemas, errorSchema)
+	}
+	return d
+}
+
+func (rt *route) operation(schemas *schemaRegistry, errorSchema *Schema) *Operation {
+	op := &Operation{
+		Tags:        []string{rt.tag()},
+		Summary:     rt.summary,
+		OperationID: rt.operationID,
+		Responses: map[string]*Response{
+			defaultResponse: {
+				Description: "Error",
+				Content:     map[string]*MediaType{contentJSON: {Schema: errorSchema}},
+			},
+		},
+	}
+	if rt.isAdmin {
+		op.Security = []map[string][]string{{securityBearer: {}}}
+	}
+	for _, name := range pathParams(rt.path) {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: name, In: "path", Required: true, Schema: &Schema{Type: typeString},
+		})
+	}
+	if rt.query != nil {
+		op.Parameters = append(op.Parameters, schemas.parameters(rt.query)...)
+	}
+	if rt.body != nil || len(rt.files) > 0 {
+		op.RequestBody = rt.requestBody(schemas)
+	}
+	status := rt.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch rt.file {
+	case "":
+		success.Content = map[string]*MediaType{contentJSON: {Schema: schemas.envelope(rt.response)}}
+	case contentJSON:
+		// The JSON which is sent as it is, without success.Success
+		success.Content = map[string]*MediaType{contentJSON: {Schema: &Schema{Type: typeObject}}}
+	default:
+		success.Content = map[string]*MediaType{rt.file: {Schema: &Schema{Type: typeString, Format: "binary"}}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	return op
+}
+
+func (rt *route) requestBody(schemas *schemaRegistry) *RequestBody {
+	if len(rt.files) == 0 {
+		return &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{contentJSON: {Schema: schemas.request(rt.body)}},
+		}
+	}
+	// The multipart form is decoded by the field names, the files are added to the fields of the request
+	form := schemas.form(rt.body)
+	for _, f := range rt.files {
+		file := &Schema{Type: typeString, Format: "binary"}
+		if f.isMultiple {
+			file = &Schema{Type: typeArray, Items: file}
+		}
+		form.Properties[f.name] = file
+		if f.isRequired {
+			form.Required = append(form.Required, f.name)
+		}
+	}
+	return &RequestBody{Required: true, Content: map[string]*MediaType{contentMultipart: {Schema: form}}}
+}
+
+// tag returns the first segment of the path, e.g. review for /review/add
+func (rt *route) tag() string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(rt.path, "/"), "/")
+	return segment
+}
+
+var fiberParamPattern = regexp.MustCompile(`:(\w+)`)
+
+// toOpenAPIPath converts the fiber path params to the OpenAPI ones, e.g. /detail/:id to /detail/{id}
+func toOpenAPIPath(path string) string {
+	return fiberParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+func pathParams(path string) []string {
+	params := make([]string, 0)
+	for _, m := range fiberParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, m[1])
+	}
+	return params
+}
+
+// Operation returns the operation of the fiber route, the path includes the server url
+func (d *Document) Operation(method string, path string) (*Operation, bool) {
+	path = strings.TrimPrefix(path, d.Servers[0].URL)
+	item, ok := d.Paths[toOpenAPIPath(path)]
+	if !ok {
+		return nil, false
+	}
+	op, ok := item[strings.ToLower(method)]
+	return op, ok
+}
+
+// CompareRoutes returns the routes under the server url which are missing in the document and the documented
+// operations which have no route, both as "METHOD path"
+func (d *Document) CompareRoutes(routes []fiber.Route) (undocumented []string, unregistered []string) {
+	prefix := d.Servers[0].URL
+	for _, rt := range routes {
+		if rt.Method == fiber.MethodHead || !strings.HasPrefix(rt.Path, prefix+"/") {
+			continue
+		}
+		if _, ok := d.Operation(rt.Method, rt.Path); !ok {
+			undocumented = append(undocumented, rt.Method+" "+rt.Path)
+		}
+	}
+	for path, item := range d.Paths {
+		for method := range item {
+			fiberPath := prefix + strings.NewReplacer("{", ":", "}", "").Replace(path)
+			if !hasRoute(routes, strings.ToUpper(method), fiberPath) {
+				unregistered = append(unregistered, strings.ToUpper(method)+" "+fiberPath)
+			}
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(unregistered)
+	return undocumented, unregistered
+}
+
+func hasRoute(routes []fiber.Route, method string, path string) bool {
+	for _, rt := range routes {
+		if rt.Method == method && rt.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/useCase/user"
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+// route describes the route of app.InitPublicRoutes or app.InitProtectedRoutes, the path is relative to the prefix.
+// The body, the query and the response are the zero values of the types which the handler decodes and writes, the
+// response is the data of success.Success.
+type route struct {
+	method      string
+	path        string
+	summary     string
+	operationID string
+	isAdmin     bool
+	body        interface{}
+	query       interface{}
+	files       []formFile
+	response    interface{}
+	// status is the status of the success response, 200 by default
+	status int
+	// file is the content type of the response which is sent as a file instead of success.Success
+	file string
+}
+
+// formFile is the file field of the multipart form
+type formFile struct {
+	name       string
+	isMultiple bool
+	isRequired bool
+}
+
+// routes has to be changed together with app.InitPublicRoutes and app.InitProtectedRoutes, the difference is logged
+// on the start of the server
+var routes = []*route{
+	{method: fiber.MethodGet, path: "/openapi.json", summary: "OpenAPI document of the api",
+		operationID: "getOpenAPI", file: contentJSON},
+
+	{method: fiber.MethodPost, path: "/user/register", summary: "Register the user", operationID: "registerUser",
+		body: user.RegisterRequest{}, response: &gocloak.User{}, status: http.StatusCreated},
+	{method: fiber.MethodPut, path: "/user/update", summary: "Update the user", operationID: "updateUser",
+		body: user.RequestUpdateUser{}, response: &gocloak.User{}, status: http.StatusCreated},
+	{method: fiber.MethodDelete, path: "/user/delete", summary: "Delete the user", operationID: "deleteUser",
+		body: user.RequestDeleteUser{}, status: http.StatusCreated},
+
+	{method: fiber.MethodPost, path: "/profile/add", summary: "Add the profile", operationID: "addProfile",
+		body: profile.RequestAddProfile{}, files: []formFile{{name: "image", isMultiple: true}},
+		response: &profile.Profile{}, status: http.StatusCreated},
+	{method: fiber.MethodGet, path: "/profile/list", summary: "List the candidates of the feed",
+		operationID: "getProfileList", query: profile.QueryParamsProfileList{},
+		response: &profile.ResponseListProfile{}},
+	{method: fiber.MethodGet, path: "/profile/session/:id", summary: "Get the profile by the session id",
+		operationID: "getProfileBySessionID", query: profile.QueryParamsGetProfileByUserID{},
+		response: &profile.ResponseProfile{}},
+	{method: fiber.MethodGet, path: "/profile/detail/:id", summary: "Get the profile shown to the viewer",
+		operationID: "getProfileDetail", query: profile.QueryParamsGetProfileDetail{},
+		response: &profile.ResponseProfileDetail{}},
+	{method: fiber.MethodPost, path: "/profile/edit", summary: "Update the profile", operationID: "updateProfile",
+		body: profile.RequestUpdateProfile{}, files: []formFile{{name: "image", isMultiple: true}},
+		response: &profile.Profile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/profile/delete", summary: "Delete the profile", operationID: "deleteProfile",
+		body: profile.RequestDeleteProfile{}, response: &profile.Profile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/profile/restore", summary: "Restore the deleted profile",
+		operationID: "restoreProfile", body: profile.RequestRestoreProfile{}, response: &profile.Profile{}},
+	{method: fiber.MethodPost, path: "/profile/image/delete", summary: "Delete the image of the profile",
+		operationID: "deleteProfileImage", body: profile.RequestDeleteProfileImage{},
+		response: &profile.ImageProfile{}, status: http.StatusCreated},
+
+	{method: fiber.MethodPost, path: "/review/add", summary: "Add the review", operationID: "addReview",
+		body: profile.RequestAddReview{}, response: &profile.ReviewProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/review/update", summary: "Update the review", operationID: "updateReview",
+		body: profile.RequestUpdateReview{}, response: &profile.ReviewProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/review/delete", summary: "Delete the review", operationID: "deleteReview",
+		body: profile.RequestDeleteReview{}, response: &profile.ReviewProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodGet, path: "/review/list", summary: "List the published reviews",
+		operationID: "getReviewList", query: profile.QueryParamsReviewList{},
+		response: &profile.ResponseListReview{}},
+	{method: fiber.MethodGet, path: "/review/detail/:id", summary: "Get the review", operationID: "getReviewByID",
+		query: profile.QueryParamsReviewDetail{}, response: &profile.ResponseReviewProfile{}},
+	{method: fiber.MethodPost, path: "/review/report", summary: "Report the review", operationID: "addReviewReport",
+		body: profile.RequestAddReviewReport{}, response: &profile.ReviewReportProfile{},
+		status: http.StatusCreated},
+
+	{method: fiber.MethodPost, path: "/like/add", summary: "Like the profile", operationID: "addLike",
+		body: profile.RequestAddLike{}, response: &profile.LikeProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPut, path: "/like/update", summary: "Update the like", operationID: "updateLike",
+		body: profile.RequestUpdateLike{}, response: &profile.LikeProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/like/delete", summary: "Delete the like", operationID: "deleteLike",
+		body: profile.RequestDeleteLike{}, response: &profile.LikeProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodGet, path: "/like/incoming", summary: "List the likes of the profile",
+		operationID: "getIncomingLikeList", query: profile.QueryParamsLikeList{},
+		response: &profile.ResponseListLike{}},
+	{method: fiber.MethodGet, path: "/like/outgoing", summary: "List the likes by the profile",
+		operationID: "getOutgoingLikeList", query: profile.QueryParamsLikeList{},
+		response: &profile.ResponseListLike{}},
+	{method: fiber.MethodGet, path: "/like/history", summary: "List the likes including the deleted ones",
+		operationID: "getLikeHistory", query: profile.QueryParamsLikeList{}, response: &profile.ResponseListLike{}},
+
+	{method: fiber.MethodPost, path: "/swipe/add", summary: "Swipe the profile", operationID: "addSwipe",
+		body: profile.RequestAddSwipe{}, response: &profile.SwipeProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/swipe/rewind", summary: "Rewind the last swipe", operationID: "rewindSwipe",
+		body: profile.RequestRewindSwipe{}, response: &profile.SwipeProfile{}, status: http.StatusCreated},
+
+	{method: fiber.MethodGet, path: "/subscription/session/:id", summary: "Get the subscription by the session id",
+		operationID: "getSubscriptionBySessionID", response: &profile.SubscriptionProfile{}},
+
+	{method: fiber.MethodPost, path: "/payment/add", summary: "Start the payment of the subscription",
+		operationID: "addPayment", body: profile.RequestAddPayment{}, response: &profile.ResponseAddPayment{},
+		status: http.StatusCreated},
+
+	{method: fiber.MethodGet, path: "/visit/list", summary: "List the visitors of the profile",
+		operationID: "getVisitList", query: profile.QueryParamsVisitList{}, response: &profile.ResponseListVisit{}},
+
+	{method: fiber.MethodPost, path: "/export/add", summary: "Request the export of the profile data",
+		operationID: "addExport", body: profile.RequestAddExport{}, response: &profile.ExportProfile{},
+		status: http.StatusCreated},
+	{method: fiber.MethodGet, path: "/export/detail/:id", summary: "Get the export with the download url",
+		operationID: "getExportByID", query: profile.QueryParamsExportDetail{},
+		response: &profile.ResponseExportProfile{}},
+	{method: fiber.MethodGet, path: "/export/download/:id", summary: "Download the export archive",
+		operationID: "downloadExport", query: profile.QueryParamsExportDownload{}, file: "application/zip"},
+
+	{method: fiber.MethodPost, path: "/block/add", summary: "Block the profile", operationID: "addBlock",
+		body: profile.RequestAddBlock{}, response: &profile.BlockedProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPut, path: "/block/update", summary: "Update the block", operationID: "updateBlock",
+		body: profile.RequestUpdateBlock{}, response: &profile.BlockedProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/block/delete", summary: "Unblock the profile", operationID: "deleteBlock",
+		body: profile.RequestDeleteBlock{}, response: &profile.BlockedProfile{}},
+	{method: fiber.MethodGet, path: "/block/list", summary: "List the blocked profiles",
+		operationID: "getBlockList", query: profile.QueryParamsBlockList{}, response: &profile.ResponseListBlock{}},
+
+	{method: fiber.MethodPost, path: "/complaint/add", summary: "Complain about the profile",
+		operationID: "addComplaint", body: profile.RequestAddComplaint{}, response: &profile.ComplaintProfile{},
+		status: http.StatusCreated},
+
+	{method: fiber.MethodPost, path: "/profile/verification/challenge", summary: "Get the pose of the selfie",
+		operationID: "addSelfieChallenge", body: profile.RequestAddSelfieChallenge{},
+		response: &profile.SelfieVerificationProfile{}, status: http.StatusCreated},
+	{method: fiber.MethodPost, path: "/profile/verification/selfie", summary: "Upload the selfie of the challenge",
+		operationID: "addSelfie", body: profile.RequestAddSelfie{}, response: &profile.SelfieVerificationProfile{},
+		files: []formFile{{name: "image", isRequired: true}}, status: http.StatusCreated},
+
+	{method: fiber.MethodPost, path: "/subscription/add", summary: "Add the subscription", isAdmin: true,
+		operationID: "addSubscription", body: profile.RequestAddSubscription{},
+		response: &profile.SubscriptionProfile{}, status: http.StatusCreated},
+
+	{method: fiber.MethodGet, path: "/review/moderation/list", summary: "List the reviews for the moderation",
+		isAdmin: true, operationID: "getReviewModerationList", query: profile.QueryParamsReviewModerationList{},
+		response: &profile.ResponseListReviewModeration{}},
+	{method: fiber.MethodPost, path: "/review/approve", summary: "Publish the review", isAdmin: true,
+		operationID: "approveReview", body: profile.RequestModerateReview{}, response: &profile.ReviewProfile{}},
+	{method: fiber.MethodPost, path: "/review/reject", summary: "Reject the review", isAdmin: true,
+		operationID: "rejectReview", body: profile.RequestModerateReview{}, response: &profile.ReviewProfile{}},
+
+	{method: fiber.MethodGet, path: "/profile/age/moderation/list", summary: "List the age checks",
+		isAdmin: true, operationID: "getAgeVerificationList", query: profile.QueryParamsAgeVerificationList{},
+		response: &profile.ResponseListAgeVerification{}},
+	{method: fiber.MethodPost, path: "/profile/age/confirm", summary: "Confirm the age of the profile",
+		isAdmin: true, operationID: "confirmAgeVerification", body: profile.RequestModerateAgeVerification{},
+		response: &profile.AgeVerificationProfile{}},
+	{method: fiber.MethodPost, path: "/profile/age/reject", summary: "Block the profile of the minor",
+		isAdmin: true, operationID: "rejectAgeVerification", body: profile.RequestModerateAgeVerification{},
+		response: &profile.AgeVerificationProfile{}},
+
+	{method: fiber.MethodGet, path: "/profile/verification/moderation/list", summary: "List the selfies",
+		isAdmin: true, operationID: "getSelfieModerationList", query: profile.QueryParamsSelfieModerationList{},
+		response: &profile.ResponseListSelfieModeration{}},
+	{method: fiber.MethodGet, path: "/profile/verification/selfie/:id", summary: "Get the image of the selfie",
+		isAdmin: true, operationID: "getSelfie", file: "image/*"},
+	{method: fiber.MethodPost, path: "/profile/verification/approve", summary: "Verify the profile by the selfie",
+		isAdmin: true, operationID: "approveSelfie", body: profile.RequestModerateSelfie{},
+		response: &profile.SelfieVerificationProfile{}},
+	{method: fiber.MethodPost, path: "/profile/verification/reject", summary: "Reject the selfie",
+		isAdmin: true, operationID: "rejectSelfie", body: profile.RequestModerateSelfie{},
+		response: &profile.SelfieVerificationProfile{}},
+}
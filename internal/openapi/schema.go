@@ -0,0 +1,307 @@
+package openapi
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/entity/pagination"
+	errorDomain "github.com/EvgeniyBudaev/love-server/internal/handler/http/api/v1/error"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	typeObject  = "object"
+	typeArray   = "array"
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+)
+
+// Schema is the subset of the OpenAPI schema which is derived from the Go types
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *uint64            `json:"minLength,omitempty"`
+	MaxLength            *uint64            `json:"maxLength,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	paginationType = reflect.TypeOf(pagination.Pagination{})
+	errorType      = reflect.TypeOf(errorDomain.ResponseError{})
+)
+
+// schemaMode tells how the fields of the struct are named and which of them are required. The response has every
+// field which is not omitempty, the request has the fields with the required rule in the validate tag.
+type schemaMode int
+
+const (
+	modeResponse schemaMode = iota
+	modeRequest
+	// modeForm names the fields as the fiber form and query decoder matches them
+	modeForm
+)
+
+// schemaRegistry keeps the schemas of the named structs as components, they are referenced by the name
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: make(map[string]*Schema), types: make(map[string]reflect.Type)}
+}
+
+// envelope returns the schema of success.Success with the data of the type of v
+func (r *schemaRegistry) envelope(v interface{}) *Schema {
+	data := &Schema{Nullable: true}
+	if v != nil {
+		data = r.response(reflect.TypeOf(v))
+	}
+	return &Schema{
+		Type: typeObject,
+		Properties: map[string]*Schema{
+			"data":       data,
+			"success":    {Type: typeBoolean},
+			"statusCode": {Type: typeInteger},
+		},
+		Required: []string{"data", "success", "statusCode"},
+	}
+}
+
+func (r *schemaRegistry) response(t reflect.Type) *Schema {
+	return r.schemaOf(t, modeResponse)
+}
+
+func (r *schemaRegistry) request(v interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(v), modeRequest)
+}
+
+// form returns the inline schema of the multipart form, so the file fields can be added to it
+func (r *schemaRegistry) form(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{Type: typeObject, Properties: make(map[string]*Schema)}
+	}
+	return r.structSchema(indirect(reflect.TypeOf(v)), modeForm)
+}
+
+// parameters returns the query parameters of the fields of v. Only the page and the size of the embedded
+// pagination are set by the client.
+func (r *schemaRegistry) parameters(v interface{}) []*Parameter {
+	s := r.structSchema(indirect(reflect.TypeOf(v)), modeForm)
+	params := make([]*Parameter, 0, len(s.Properties))
+	for _, f := range fields(indirect(reflect.TypeOf(v)), modeForm) {
+		params = append(params, &Parameter{
+			Name:     f.name,
+			In:       "query",
+			Required: contains(s.Required, f.name),
+			Schema:   s.Properties[f.name],
+		})
+	}
+	return params
+}
+
+func (r *schemaRegistry) schemaOf(t reflect.Type, mode schemaMode) *Schema {
+	switch {
+	case t == nil:
+		return &Schema{}
+	case t == timeType:
+		return &Schema{Type: typeString, Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.schemaOf(t.Elem(), mode))
+	case reflect.Bool:
+		return &Schema{Type: typeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: typeInteger, Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: typeInteger, Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: typeInteger, Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: typeNumber, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: typeNumber, Format: "double"}
+	case reflect.String:
+		return &Schema{Type: typeString}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: typeString, Format: "byte"}
+		}
+		return &Schema{Type: typeArray, Items: r.schemaOf(t.Elem(), mode), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: typeObject, AdditionalProperties: r.schemaOf(t.Elem(), mode), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" || mode == modeForm {
+			return r.structSchema(t, mode)
+		}
+		return r.ref(t, mode)
+	}
+	// The interfaces may hold any value
+	return &Schema{}
+}
+
+// ref registers the named struct as the component and returns the reference to it
+func (r *schemaRegistry) ref(t reflect.Type, mode schemaMode) *Schema {
+	name := t.Name()
+	if known, ok := r.types[name]; ok && known != t {
+		// The structs of the different packages may have the same name
+		name = packageName(t) + name
+	}
+	if _, ok := r.types[name]; !ok {
+		r.types[name] = t
+		// The placeholder stops the recursion of the self referencing structs
+		r.schemas[name] = &Schema{}
+		*r.schemas[name] = *r.structSchema(t, mode)
+	}
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type, mode schemaMode) *Schema {
+	s := &Schema{Type: typeObject, Properties: make(map[string]*Schema)}
+	for _, f := range fields(t, mode) {
+		property := r.schemaOf(f.field.Type, mode)
+		applyRules(property, f.rules)
+		s.Properties[f.name] = property
+		if f.isRequired {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+type structField struct {
+	field      reflect.StructField
+	name       string
+	rules      []string
+	isRequired bool
+}
+
+// fields returns the serialized fields of the struct, the fields of the embedded structs are promoted
+func fields(t reflect.Type, mode schemaMode) []*structField {
+	list := make([]*structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && name == "" && indirect(f.Type).Kind() == reflect.Struct {
+			embedded := fields(indirect(f.Type), mode)
+			if mode == modeForm && indirect(f.Type) == paginationType {
+				embedded = pick(embedded, "page", "size")
+			}
+			list = append(list, embedded...)
+			continue
+		}
+		if name == "" || (mode == modeForm && !strings.EqualFold(name, f.Name)) {
+			// The fiber decoder matches the form and query values by the field name regardless of the case
+			name = f.Name
+		}
+		rules := validateRules(f)
+		isRequired := contains(rules, "required")
+		if mode == modeResponse {
+			isRequired = !strings.Contains(options, "omitempty")
+		}
+		list = append(list, &structField{field: f, name: name, rules: rules, isRequired: isRequired})
+	}
+	return list
+}
+
+func validateRules(f reflect.StructField) []string {
+	tag := f.Tag.Get("validate")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// applyRules sets the limits of the validate rules which can be expressed in the schema
+func applyRules(s *Schema, rules []string) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "min", "gte":
+			setLimit(s, param, &s.Minimum, &s.MinLength)
+		case "max", "lte":
+			setLimit(s, param, &s.Maximum, &s.MaxLength)
+		case "latitude", "longitude", "boolean":
+			s.Format = name
+		}
+	}
+}
+
+func setLimit(s *Schema, param string, value **float64, length **uint64) {
+	if param == "" {
+		return
+	}
+	switch s.Type {
+	case typeString:
+		if n, err := strconv.ParseUint(param, 10, 64); err == nil {
+			*length = &n
+		}
+	case typeInteger, typeNumber:
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			*value = &n
+		}
+	}
+}
+
+// nullable marks the schema as nullable, the reference can't have siblings so it is wrapped
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func packageName(t reflect.Type) string {
+	path := strings.Split(t.PkgPath(), "/")
+	name := path[len(path)-1]
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func pick(list []*structField, names ...string) []*structField {
+	picked := make([]*structField, 0, len(names))
+	for _, f := range list {
+		if contains(names, f.name) {
+			picked = append(picked, f)
+		}
+	}
+	return picked
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// ValidateResponse checks the JSON body of the response of the route against the document and returns the
+// violations. The types, the nullable values, the required and the unknown properties and the enums are checked, the
+// limits of the requests are not. The routes which are not documented and the files are skipped.
+func (d *Document) ValidateResponse(method string, path string, status int, body []byte) []string {
+	op, ok := d.Operation(method, path)
+	if !ok {
+		return nil
+	}
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if status < 400 {
+			return []string{fmt.Sprintf("status %d is not documented", status)}
+		}
+		response = op.Responses[defaultResponse]
+	}
+	media, ok := response.Content[contentJSON]
+	if !ok {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []string{"body is not JSON: " + err.Error()}
+	}
+	violations := make([]string, 0)
+	d.validate(value, media.Schema, "body", &violations)
+	return violations
+}
+
+func (d *Document) validate(value interface{}, s *Schema, location string, violations *[]string) {
+	if value == nil {
+		if !s.Nullable && (s.Ref != "" || s.Type != "" || len(s.AllOf) > 0) {
+			*violations = append(*violations, location+" is null")
+		}
+		return
+	}
+	if s.Ref != "" {
+		d.validate(value, d.Components.Schemas[strings.TrimPrefix(s.Ref, schemaRefPrefix)], location, violations)
+		return
+	}
+	for _, sub := range s.AllOf {
+		d.validate(value, sub, location, violations)
+	}
+	switch s.Type {
+	case typeObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			*violations = append(*violations, location+" is not an object")
+			return
+		}
+		d.validateObject(object, s, location, violations)
+	case typeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			*violations = append(*violations, location+" is not an array")
+			return
+		}
+		for i, item := range items {
+			d.validate(item, s.Items, fmt.Sprintf("%s[%d]", location, i), violations)
+		}
+	case typeString:
+		str, ok := value.(string)
+		if !ok {
+			*violations = append(*violations, location+" is not a string")
+			return
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			*violations = append(*violations, fmt.Sprintf("%s %q is not one of %v", location, str, s.Enum))
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				*violations = append(*violations, location+" is not a date-time")
+			}
+		}
+	case typeInteger:
+		n, ok := value.(json.Number)
+		if !ok || strings.ContainsAny(n.String(), ".eE") {
+			*violations = append(*violations, location+" is not an integer")
+		}
+	case typeNumber:
+		if _, ok := value.(json.Number); !ok {
+			*violations = append(*violations, location+" is not a number")
+		}
+	case typeBoolean:
+		if _, ok := value.(bool); !ok {
+			*violations = append(*violations, location+" is not a boolean")
+		}
+	}
+}
+
+func (d *Document) validateObject(object map[string]interface{}, s *Schema, location string, violations *[]string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*violations = append(*violations, location+"."+name+" is required")
+		}
+	}
+	for name, v := range object {
+		property, ok := s.Properties[name]
+		switch {
+		case ok:
+			d.validate(v, property, location+"."+name, violations)
+		case s.AdditionalProperties != nil:
+			d.validate(v, s.AdditionalProperties, location+"."+name, violations)
+		case len(s.Properties) > 0:
+			*violations = append(*violations, location+"."+name+" is not documented")
+		}
+	}
+}
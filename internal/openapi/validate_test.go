@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestValidateResponse(t *testing.T) {
+	d := NewDocument("love-server", "1.0.0", "/api/v1")
+	const route = "/api/v1/review/detail/:id"
+	review := `{"id":1,"profileId":2,"humanId":3,"message":"hello","rating":4.5,"hasDeleted":false,` +
+		`"hasEdited":false,"status":"published","createdAt":"2026-10-19T10:00:00Z",` +
+		`"updatedAt":"2026-10-19T10:00:00Z","sessionId":"owner"}`
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		violation string
+	}{
+		{name: "valid", status: http.StatusOK,
+			body: `{"data":` + review + `,"success":true,"statusCode":200}`},
+		{name: "valid error", status: http.StatusNotFound,
+			body: `{"statusCode":404,"success":false,"message":"review is not found","code":"not_found"}`},
+		{name: "missing property", status: http.StatusOK,
+			body: `{"data":` + review + `,"statusCode":200}`, violation: "body.success is required"},
+		{name: "wrong type", status: http.StatusOK,
+			body: `{"data":` + strings.Replace(review, `"id":1`, `"id":"1"`, 1) +
+				`,"success":true,"statusCode":200}`,
+			violation: "body.data.id is not an integer"},
+		{name: "undocumented property", status: http.StatusOK,
+			body:      `{"data":` + review + `,"success":true,"statusCode":200,"extra":1}`,
+			violation: "body.extra is not documented"},
+		{name: "invalid date", status: http.StatusOK,
+			body: `{"data":` + strings.Replace(review, `"createdAt":"2026-10-19T10:00:00Z"`, `"createdAt":"today"`, 1) +
+				`,"success":true,"statusCode":200}`,
+			violation: "body.data.createdAt is not a date-time"},
+		{name: "undocumented status", status: http.StatusCreated,
+			body: `{"data":null,"success":true,"statusCode":201}`, violation: "status 201 is not documented"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := d.ValidateResponse(http.MethodGet, route, tt.status, []byte(tt.body))
+			if tt.violation == "" {
+				if len(violations) > 0 {
+					t.Fatalf("unexpected violations: %v", violations)
+				}
+				return
+			}
+			if !contains(violations, tt.violation) {
+				t.Fatalf("expected violation %q, got %v", tt.violation, violations)
+			}
+		})
+	}
+}
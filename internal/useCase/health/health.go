@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/health"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"go.uber.org/zap"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkTimeout limits every check, so a hanging dependency does not hang the probe
+const checkTimeout = 3 * time.Second
+
+type Store interface {
+	Ping(ctx context.Context) error
+	FindMigrationVersion(ctx context.Context) (*health.MigrationVersion, bool, error)
+}
+
+// IdentityPinger checks that the identity provider is reachable
+type IdentityPinger interface {
+	Ping(ctx context.Context) error
+}
+
+type UseCaseHealth struct {
+	logger             logger.Logger
+	healthRepo         Store
+	identity           IdentityPinger
+	storageDirectories []string
+	migrationVersion   uint64
+}
+
+// NewUseCaseHealth returns the use case which checks the database, the writability of the storage directories,
+// the identity provider and the applied migration. The identity check is skipped when ip is nil, only a dirty or
+// missing migration fails the check when migrationVersion is 0.
+func NewUseCaseHealth(l logger.Logger, hr Store, ip IdentityPinger, storageDirectories []string,
+	migrationVersion uint64) *UseCaseHealth {
+	return &UseCaseHealth{
+		logger:             l,
+		healthRepo:         hr,
+		identity:           ip,
+		storageDirectories: storageDirectories,
+		migrationVersion:   migrationVersion,
+	}
+}
+
+// CheckReadiness runs the checks concurrently, the service is ready when none of them is down
+func (u *UseCaseHealth) CheckReadiness(ctx context.Context) *health.ResponseReadiness {
+	checks := []struct {
+		name  string
+		check func(ctx context.Context) error
+	}{
+		{name: health.CheckDatabase, check: u.healthRepo.Ping},
+		{name: health.CheckStorage, check: u.checkStorage},
+		{name: health.CheckIdentity, check: u.checkIdentity},
+		{name: health.CheckMigration, check: u.checkMigration},
+	}
+	results := make([]*health.ResponseCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			results[i] = u.run(ctx, name, check)
+		}(i, c.name, c.check)
+	}
+	wg.Wait()
+	response := health.ResponseReadiness{Status: health.StatusUp, Checks: results}
+	for _, r := range results {
+		if r.Status == health.StatusDown {
+			response.Status = health.StatusDown
+		}
+	}
+	return &response
+}
+
+func (u *UseCaseHealth) run(
+	ctx context.Context, name string, check func(ctx context.Context) error) *health.ResponseCheck {
+	if name == health.CheckIdentity && u.identity == nil {
+		return &health.ResponseCheck{Name: name, Status: health.StatusSkipped}
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	startedAt := time.Now()
+	err := check(checkCtx)
+	response := health.ResponseCheck{
+		Name:      name,
+		Status:    health.StatusUp,
+		LatencyMs: time.Since(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		u.logger.With(ctx).Warn("error func run, method check", zap.String("check", name), zap.Error(err))
+		response.Status = health.StatusDown
+		response.Message = err.Error()
+	}
+	return &response
+}
+
+// checkStorage creates and removes a file in every storage directory
+func (u *UseCaseHealth) checkStorage(_ context.Context) error {
+	for _, dir := range u.storageDirectories {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		file, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return err
+		}
+		name := file.Name()
+		if err := file.Close(); err != nil {
+			_ = os.Remove(name)
+			return err
+		}
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UseCaseHealth) checkIdentity(ctx context.Context) error {
+	return u.identity.Ping(ctx)
+}
+
+func (u *UseCaseHealth) checkMigration(ctx context.Context) error {
+	v, isExist, err := u.healthRepo.FindMigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		return fmt.Errorf("no migration has been applied")
+	}
+	if v.Dirty {
+		return fmt.Errorf("migration %d is dirty", v.Version)
+	}
+	if u.migrationVersion != 0 && v.Version != u.migrationVersion {
+		return fmt.Errorf("migration version is %d, expected %d", v.Version, u.migrationVersion)
+	}
+	return nil
+}
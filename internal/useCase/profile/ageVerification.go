@@ -0,0 +1,93 @@
+package profile
+
+import (
+	"context"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	"go.uber.org/zap"
+	"time"
+)
+
+// RequestAgeVerification holds the profile of the suspected minor for a moderator. The profile has at most one
+// pending check, it is returned when it exists already.
+func (u *UseCaseProfile) RequestAgeVerification(
+	ctx context.Context, profileID uint64, reason string) (*profile.AgeVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.RequestAgeVerification")
+	defer span.End()
+	pending, isExist, err := u.profileRepo.FindPendingAgeVerificationByProfileID(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func RequestAgeVerification, method FindPendingAgeVerificationByProfileID"+
+			"", zap.Error(err))
+		return nil, err
+	}
+	if isExist {
+		return pending, nil
+	}
+	verificationDto := &profile.AgeVerificationProfile{
+		ProfileID: profileID,
+		Status:    profile.AgeVerificationStatusPending,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	response, err := u.profileRepo.AddAgeVerification(ctx, verificationDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func RequestAgeVerification, method AddAgeVerification", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+// ModerateAgeVerification sets the status chosen by a moderator to the pending check. The profile whose age is
+// not confirmed is blocked.
+func (u *UseCaseProfile) ModerateAgeVerification(
+	ctx context.Context, id uint64, status string) (*profile.AgeVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ModerateAgeVerification")
+	defer span.End()
+	verification, isExist, err := u.profileRepo.FindAgeVerificationByID(ctx, id)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ModerateAgeVerification, method FindAgeVerificationByID", zap.Error(err))
+		return nil, err
+	}
+	if !isExist {
+		return nil, appError.NewNotFound("age verification not found")
+	}
+	if verification.Status != profile.AgeVerificationStatusPending {
+		return nil, appError.NewConflict("age verification has already been moderated")
+	}
+	if status == profile.AgeVerificationStatusRejected {
+		p, err := u.profileRepo.FindById(ctx, verification.ProfileID)
+		if err != nil {
+			u.logger.With(ctx).Error("error func ModerateAgeVerification, method FindById", zap.Error(err))
+			return nil, err
+		}
+		p.IsBlocked = true
+		p.UpdatedAt = time.Now().UTC()
+		if _, err := u.profileRepo.Update(ctx, p); err != nil {
+			u.logger.With(ctx).Error("error func ModerateAgeVerification, method Update", zap.Error(err))
+			return nil, err
+		}
+	}
+	verification.Status = status
+	verification.UpdatedAt = time.Now().UTC()
+	response, err := u.profileRepo.UpdateAgeVerification(ctx, verification)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ModerateAgeVerification, method UpdateAgeVerification", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) SelectListAgeVerificationByStatus(ctx context.Context,
+	qp *profile.QueryParamsAgeVerificationList) (*profile.ResponseListAgeVerification, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListAgeVerificationByStatus")
+	defer span.End()
+	response, err := u.profileRepo.SelectListAgeVerificationByStatus(ctx, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListAgeVerificationByStatus,"+
+			" method SelectListAgeVerificationByStatus", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,157 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/Nerzal/gocloak/v13"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// IdentityProvider removes the user of the profile from the identity provider
+type IdentityProvider interface {
+	DeleteUser(ctx context.Context, user gocloak.User) error
+}
+
+// ProfileEraser erases the personal data of the profiles which have been deleted more than
+// profile.DeletionGracePeriod ago: the rows in the profile_* tables, the image, export and selfie files and the
+// identity provider user. An audit record of what has been removed is kept for every profile.
+type ProfileEraser struct {
+	logger      logger.Logger
+	profileRepo Store
+	identity    IdentityProvider
+}
+
+func NewProfileEraser(l logger.Logger, pr Store, ip IdentityProvider) *ProfileEraser {
+	return &ProfileEraser{
+		logger:      l,
+		profileRepo: pr,
+		identity:    ip,
+	}
+}
+
+// EraseDeleted erases the profiles whose grace period has passed by the moment and returns their number.
+// A profile which fails to be erased is left for the next run.
+func (e *ProfileEraser) EraseDeleted(ctx context.Context, moment time.Time) (int, error) {
+	list, err := e.profileRepo.SelectListDeletedBefore(ctx, moment.Add(-profile.DeletionGracePeriod))
+	if err != nil {
+		e.logger.With(ctx).Error("error func EraseDeleted, method SelectListDeletedBefore", zap.Error(err))
+		return 0, err
+	}
+	count := 0
+	for _, p := range list {
+		if err := e.erase(ctx, p, moment); err != nil {
+			e.logger.Error("error func EraseDeleted, method erase", zap.Uint64("profileId", p.ID), zap.Error(err))
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (e *ProfileEraser) erase(ctx context.Context, p *profile.DeletedProfile, moment time.Time) error {
+	isIdentityRemoved, err := e.deleteIdentity(ctx, p.SessionID)
+	if err != nil {
+		return err
+	}
+	images, err := e.profileRepo.SelectListImage(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	exports, err := e.profileRepo.SelectListExportByProfileID(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	selfies, err := e.profileRepo.SelectListSelfieVerificationByProfileID(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	removedFiles := removeImageFiles(images) + removeExportFiles(exports) + removeSelfieFiles(selfies)
+	removedRows, err := e.profileRepo.Erase(ctx, p.ID, moment)
+	if err != nil {
+		return err
+	}
+	audit := &profile.DeletionAuditProfile{
+		ProfileID:         p.ID,
+		DeletedAt:         p.DeletedAt,
+		ErasedAt:          moment,
+		RemovedRows:       removedRows,
+		RemovedFiles:      removedFiles,
+		IsIdentityRemoved: isIdentityRemoved,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if _, err := e.profileRepo.AddDeletionAudit(ctx, audit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteIdentity deletes the identity provider user, the user which does not exist any more is not an error
+func (e *ProfileEraser) deleteIdentity(ctx context.Context, sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	err := e.identity.DeleteUser(ctx, gocloak.User{ID: gocloak.StringP(sessionID)})
+	var apiErr *gocloak.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removeImageFiles removes the image files and the directories left empty, the number of removed files is returned
+func removeImageFiles(images []*profile.ImageProfile) int {
+	count := 0
+	dirs := make(map[string]struct{})
+	for _, i := range images {
+		if i.Url == "" {
+			continue
+		}
+		if err := os.Remove(i.Url); err == nil {
+			count++
+		}
+		dirs[filepath.Dir(i.Url)] = struct{}{}
+	}
+	for dir := range dirs {
+		// Only the empty directories are removed, so the errors are expected
+		_ = os.Remove(dir)
+		_ = os.Remove(filepath.Dir(dir))
+	}
+	return count
+}
+
+// removeExportFiles removes the export archives, the number of removed files is returned
+func removeExportFiles(exports []*profile.ExportProfile) int {
+	count := 0
+	for _, ex := range exports {
+		if ex.FilePath == "" {
+			continue
+		}
+		if err := os.Remove(ex.FilePath); err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// removeSelfieFiles removes the selfies of the verification checks, the number of removed files is returned
+func removeSelfieFiles(selfies []*profile.SelfieVerificationProfile) int {
+	count := 0
+	for _, v := range selfies {
+		if v.FilePath == "" {
+			continue
+		}
+		if err := os.Remove(v.FilePath); err == nil {
+			count++
+		}
+	}
+	return count
+}
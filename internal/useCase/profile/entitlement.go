@@ -0,0 +1,35 @@
+package profile
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+)
+
+// EntitlementChecker decides whether the profile may use the feature
+type EntitlementChecker interface {
+	IsEntitled(p *profile.Profile, feature string) bool
+}
+
+// PremiumEntitlementChecker grants the premium features to the profiles with an active subscription.
+// The IsPremium flag is set when a subscription is activated and reset when the last one expires.
+type PremiumEntitlementChecker struct {
+	premiumFeatures map[string]struct{}
+}
+
+func NewPremiumEntitlementChecker() *PremiumEntitlementChecker {
+	return &PremiumEntitlementChecker{
+		premiumFeatures: map[string]struct{}{
+			profile.FeatureInvisible:      {},
+			profile.FeatureUnlimitedLikes: {},
+			profile.FeatureWhoLikedMe:     {},
+			profile.FeatureRewind:         {},
+			profile.FeaturePremiumLimits:  {},
+		},
+	}
+}
+
+func (c *PremiumEntitlementChecker) IsEntitled(p *profile.Profile, feature string) bool {
+	if _, ok := c.premiumFeatures[feature]; !ok {
+		return true
+	}
+	return p.IsPremium
+}
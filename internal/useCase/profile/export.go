@@ -0,0 +1,290 @@
+package profile
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExportNotifier sends the message to the telegram chat of the profile
+type ExportNotifier interface {
+	SendMessage(chatID int64, text string) error
+}
+
+func (u *UseCaseProfile) AddExport(ctx context.Context, profileID uint64) (*profile.ExportProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddExport")
+	defer span.End()
+	token, err := newExportToken()
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddExport, method newExportToken", zap.Error(err))
+		return nil, err
+	}
+	exportDto := &profile.ExportProfile{
+		ProfileID: profileID,
+		Status:    profile.ExportStatusPending,
+		Token:     token,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	response, err := u.profileRepo.AddExport(ctx, exportDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddExport, method AddExport", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) FindPendingExportByProfileID(
+	ctx context.Context, profileID uint64) (*profile.ExportProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindPendingExportByProfileID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindPendingExportByProfileID(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindPendingExportByProfileID, method FindPendingExportByProfileID",
+			zap.Error(err))
+		return nil, false, err
+	}
+	return response, isExist, nil
+}
+
+func (u *UseCaseProfile) FindExportByID(ctx context.Context, id uint64) (*profile.ExportProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindExportByID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindExportByID(ctx, id)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindExportByID, method FindExportByID", zap.Error(err))
+		return nil, false, err
+	}
+	return response, isExist, nil
+}
+
+// ProfileExporter builds the pending exports into ZIP archives with data.json and the original images, notifies
+// the profiles when their archives are ready and removes the archives after profile.ExportRetention
+type ProfileExporter struct {
+	logger      logger.Logger
+	profileRepo Store
+	notifier    ExportNotifier
+	directory   string
+	downloadURL string
+}
+
+// NewProfileExporter returns the exporter which keeps the archives in the directory, the archive with the id is
+// downloaded by downloadURL/id?token=...
+func NewProfileExporter(l logger.Logger, pr Store, n ExportNotifier, directory string,
+	downloadURL string) *ProfileExporter {
+	return &ProfileExporter{
+		logger:      l,
+		profileRepo: pr,
+		notifier:    n,
+		directory:   directory,
+		downloadURL: downloadURL,
+	}
+}
+
+// BuildPending builds the pending exports and returns the number of the ready ones
+func (e *ProfileExporter) BuildPending(ctx context.Context, moment time.Time) (int, error) {
+	list, err := e.profileRepo.SelectListExportByStatus(ctx, profile.ExportStatusPending)
+	if err != nil {
+		e.logger.With(ctx).Error("error func BuildPending, method SelectListExportByStatus", zap.Error(err))
+		return 0, err
+	}
+	count := 0
+	for _, ex := range list {
+		filePath, err := e.build(ctx, ex, moment)
+		ex.UpdatedAt = time.Now().UTC()
+		if err != nil {
+			e.logger.Error("error func BuildPending, method build", zap.Uint64("exportId", ex.ID), zap.Error(err))
+			ex.Status = profile.ExportStatusFailed
+		} else {
+			expiresAt := moment.Add(profile.ExportRetention)
+			ex.Status = profile.ExportStatusReady
+			ex.FilePath = filePath
+			ex.ExpiresAt = &expiresAt
+		}
+		if _, err := e.profileRepo.UpdateExport(ctx, ex); err != nil {
+			e.logger.With(ctx).Error("error func BuildPending, method UpdateExport", zap.Error(err))
+			return count, err
+		}
+		e.notify(ctx, ex)
+		if ex.Status == profile.ExportStatusReady {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteExpired removes the archives whose retention has passed by the moment and returns their number
+func (e *ProfileExporter) DeleteExpired(ctx context.Context, moment time.Time) (int, error) {
+	list, err := e.profileRepo.SelectListExportByStatus(ctx, profile.ExportStatusReady)
+	if err != nil {
+		e.logger.With(ctx).Error("error func DeleteExpired, method SelectListExportByStatus", zap.Error(err))
+		return 0, err
+	}
+	count := 0
+	for _, ex := range list {
+		if ex.ExpiresAt == nil || ex.ExpiresAt.After(moment) {
+			continue
+		}
+		if err := os.Remove(ex.FilePath); err != nil && !os.IsNotExist(err) {
+			e.logger.With(ctx).Error("error func DeleteExpired, method Remove", zap.Error(err))
+			continue
+		}
+		ex.Status = profile.ExportStatusExpired
+		ex.FilePath = ""
+		ex.UpdatedAt = time.Now().UTC()
+		if _, err := e.profileRepo.UpdateExport(ctx, ex); err != nil {
+			e.logger.With(ctx).Error("error func DeleteExpired, method UpdateExport", zap.Error(err))
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (e *ProfileExporter) build(ctx context.Context, ex *profile.ExportProfile, moment time.Time) (string, error) {
+	data, err := e.collect(ctx, ex.ProfileID, moment)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(e.directory, 0700); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(e.directory, fmt.Sprintf("profile-%d-export-%d.zip", ex.ProfileID, ex.ID))
+	if err := writeExportArchive(filePath, data); err != nil {
+		_ = os.Remove(filePath)
+		return "", err
+	}
+	return filePath, nil
+}
+
+// collect gathers the personal data of the profile, the telegram, navigator and filter rows may be absent. The
+// navigator is exported with its move metadata, only the last location of the profile is stored.
+func (e *ProfileExporter) collect(
+	ctx context.Context, profileID uint64, moment time.Time) (*profile.ExportDataProfile, error) {
+	var err error
+	data := &profile.ExportDataProfile{ExportedAt: moment}
+	if data.Profile, err = e.profileRepo.FindById(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.Telegram, err = e.profileRepo.FindTelegramByProfileID(ctx, profileID); err != nil &&
+		!errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	navigator, err := e.profileRepo.FindNavigatorByProfileID(ctx, profileID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	data.Navigator = profile.NewExportNavigator(navigator)
+	if data.Filter, err = e.profileRepo.FindFilterByProfileID(ctx, profileID); err != nil &&
+		!errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if data.Images, err = e.profileRepo.SelectListImage(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.LikesSent, err = e.profileRepo.SelectListLikeByProfileID(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.LikesReceived, err = e.profileRepo.SelectListLikeByHumanID(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.Blocks, err = e.profileRepo.SelectListBlockByProfileID(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.Complaints, err = e.profileRepo.SelectListComplaintByProfileID(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if data.Reviews, err = e.profileRepo.SelectListReviewByProfileID(ctx, profileID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// notify sends the download link or the failure to the telegram chat of the profile
+func (e *ProfileExporter) notify(ctx context.Context, ex *profile.ExportProfile) {
+	if e.notifier == nil {
+		return
+	}
+	t, err := e.profileRepo.FindTelegramByProfileID(ctx, ex.ProfileID)
+	if err != nil {
+		e.logger.With(ctx).Error("error func notify, method FindTelegramByProfileID", zap.Error(err))
+		return
+	}
+	text := "Не удалось подготовить архив с вашими данными, попробуйте запросить его ещё раз"
+	if ex.Status == profile.ExportStatusReady {
+		text = fmt.Sprintf("Архив с вашими данными готов, он доступен до %s: %s",
+			ex.ExpiresAt.Format("02.01.2006"), profile.GetExportDownloadURL(e.downloadURL, ex))
+	}
+	if err := e.notifier.SendMessage(int64(t.TelegramID), text); err != nil {
+		e.logger.With(ctx).Error("error func notify, method SendMessage", zap.Error(err))
+	}
+}
+
+// writeExportArchive writes data.json and the image files which still exist into the ZIP archive
+func writeExportArchive(filePath string, data *profile.ExportDataProfile) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+	w, err := archive.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+	for _, i := range data.Images {
+		if i.Url == "" {
+			continue
+		}
+		if err := addExportFile(archive, i.Url, fmt.Sprintf("images/%d-%s", i.ID, filepath.Base(i.Url))); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func addExportFile(archive *zip.Writer, filePath string, name string) error {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+func newExportToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+package profile
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"math"
+	"time"
+)
+
+const (
+	// locationJumpMinDistance is the distance in metres from which a move is stored and counted as a jump whatever
+	// its speed. It is the smallest distance band, the shorter moves are not stored, so they add up to a jump.
+	locationJumpMinDistance = 1000
+	// locationJumpLimit is the number of jumps allowed within locationJumpWindow from the first of them
+	locationJumpLimit  = 10
+	locationJumpWindow = 24 * time.Hour
+	earthRadius        = 6371000
+)
+
+// LocationPrivacy keeps the exact location of the profiles from the viewers. The viewers see bucketed distances with
+// noise which is stable for the pair of profiles, so repeated requests do not average the noise out. The viewers
+// which keep moving, e.g. to trilaterate a profile, are refused to move.
+type LocationPrivacy struct {
+	secret []byte
+}
+
+// NewLocationPrivacy returns the component with the noise secret. The secret has to be the same on every instance
+// and across the restarts, otherwise the noise of a pair changes and can be averaged out.
+func NewLocationPrivacy(secret string) (*LocationPrivacy, error) {
+	if secret == "" {
+		return nil, errors.New("location privacy secret is empty")
+	}
+	return &LocationPrivacy{secret: []byte(secret)}, nil
+}
+
+// Distance returns the distance in metres which the viewer may see, nil when the profile hides its distance
+func (lp *LocationPrivacy) Distance(p *profile.Profile, viewerID uint64, distance float64) *float64 {
+	if p.ID == viewerID {
+		return &distance
+	}
+	if !p.IsShowDistance {
+		return nil
+	}
+	fuzzed := lp.NoisyDistance(p.ID, viewerID, distance)
+	return &fuzzed
+}
+
+// NoisyDistance returns the distance in metres with the noise of the pair rounded up to its band, it is the distance
+// the viewer is shown and the feed is filtered and ordered on
+func (lp *LocationPrivacy) NoisyDistance(profileID, viewerID uint64, distance float64) float64 {
+	noisy := math.Max(distance, 0) * (1 + profile.DistanceNoiseRatio*lp.noise(profileID, viewerID))
+	return profile.BandDistance(noisy)
+}
+
+// IsShortMove reports whether the move from the current navigator to the next one is shorter than
+// locationJumpMinDistance, such a move is not stored
+func (lp *LocationPrivacy) IsShortMove(current, next *profile.NavigatorProfile) bool {
+	return current != nil && current.MovedAt != nil &&
+		haversine(current.Location, next.Location) < locationJumpMinDistance
+}
+
+// IsMoveAllowed reports whether the profile may move from the current navigator to the next one. The track of the
+// move is set on the next navigator when it is allowed.
+func (lp *LocationPrivacy) IsMoveAllowed(current, next *profile.NavigatorProfile, now time.Time) bool {
+	next.MovedAt = &now
+	next.JumpCount = 0
+	next.JumpsStartedAt = nil
+	if current == nil || current.MovedAt == nil {
+		return true
+	}
+	if current.JumpsStartedAt != nil && now.Sub(*current.JumpsStartedAt) < locationJumpWindow {
+		next.JumpCount = current.JumpCount
+		next.JumpsStartedAt = current.JumpsStartedAt
+	}
+	if haversine(current.Location, next.Location) >= locationJumpMinDistance {
+		if next.JumpCount >= locationJumpLimit {
+			return false
+		}
+		if next.JumpsStartedAt == nil {
+			next.JumpsStartedAt = &now
+		}
+		next.JumpCount++
+	}
+	return true
+}
+
+// noise returns a value in [-1, 1] which is the same for the pair of profiles in any order
+func (lp *LocationPrivacy) noise(profileID, viewerID uint64) float64 {
+	first, second := profileID, viewerID
+	if first > second {
+		first, second = second, first
+	}
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint64(buf[:8], first)
+	binary.BigEndian.PutUint64(buf[8:], second)
+	mac := hmac.New(sha256.New, lp.secret)
+	mac.Write(buf)
+	sum := binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+	return float64(sum)/float64(math.MaxUint64)*2 - 1
+}
+
+func haversine(from, to *profile.Point) float64 {
+	lat1 := from.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - from.Longitude) * math.Pi / 180
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
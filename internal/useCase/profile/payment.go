@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	"go.uber.org/zap"
+	"time"
+)
+
+// PaymentProvider creates invoices which are paid outside the app
+type PaymentProvider interface {
+	CreateInvoiceLink(p *profile.PaymentProfile) (string, error)
+}
+
+// CreateInvoice records a pending payment for the premium period and returns the link to pay it
+func (u *UseCaseProfile) CreateInvoice(
+	ctx context.Context, profileID uint64, plan string, period string) (*profile.ResponseAddPayment, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CreateInvoice")
+	defer span.End()
+	if u.payment == nil {
+		return nil, appError.NewInternal(fmt.Errorf("payments are not configured"))
+	}
+	if plan != profile.SubscriptionPlanPremium {
+		return nil, appError.NewValidation(fmt.Sprintf("unknown subscription plan: %s", plan),
+			&appError.FieldError{Field: "plan", Message: "unknown subscription plan"})
+	}
+	amount, ok := profile.SubscriptionPrices[period]
+	if !ok {
+		return nil, appError.NewValidation(fmt.Sprintf("unknown subscription period: %s", period),
+			&appError.FieldError{Field: "period", Message: "unknown subscription period"})
+	}
+	payload, err := newPaymentPayload()
+	if err != nil {
+		u.logger.With(ctx).Error("error func CreateInvoice, method newPaymentPayload", zap.Error(err))
+		return nil, err
+	}
+	paymentDto := &profile.PaymentProfile{
+		ProfileID: profileID,
+		Plan:      plan,
+		Period:    period,
+		Amount:    amount,
+		Currency:  profile.PaymentCurrencyStars,
+		Status:    profile.PaymentStatusPending,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	p, err := u.profileRepo.AddPayment(ctx, paymentDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CreateInvoice, method AddPayment", zap.Error(err))
+		return nil, err
+	}
+	link, err := u.payment.CreateInvoiceLink(p)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CreateInvoice, method CreateInvoiceLink", zap.Error(err))
+		return nil, err
+	}
+	return &profile.ResponseAddPayment{ID: p.ID, InvoiceLink: link}, nil
+}
+
+// CheckPayment validates the payment before Telegram charges the user
+func (u *UseCaseProfile) CheckPayment(ctx context.Context, c *profile.CheckoutPayment) error {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CheckPayment")
+	defer span.End()
+	_, err := u.findPendingPayment(ctx, c)
+	return err
+}
+
+// CompletePayment marks the payment as paid and activates the premium period in one transaction. A payment which
+// is not pending any more is rejected, so a repeated update does not prolong the subscription twice.
+func (u *UseCaseProfile) CompletePayment(
+	ctx context.Context, c *profile.CheckoutPayment) (*profile.SubscriptionProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CompletePayment")
+	defer span.End()
+	p, err := u.findPendingPayment(ctx, c)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CompletePayment, method findPendingPayment", zap.Error(err))
+		return nil, err
+	}
+	subscriptionDto, err := u.newSubscription(ctx, p.ProfileID, p.Plan, p.Period)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CompletePayment, method newSubscription", zap.Error(err))
+		return nil, err
+	}
+	p.Status = profile.PaymentStatusPaid
+	p.TelegramPaymentChargeID = c.TelegramPaymentChargeID
+	p.ProviderPaymentChargeID = c.ProviderPaymentChargeID
+	p.UpdatedAt = time.Now().UTC()
+	subscription, isCompleted, err := u.profileRepo.CompletePayment(ctx, p, subscriptionDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CompletePayment, method CompletePayment", zap.Error(err))
+		return nil, err
+	}
+	if !isCompleted {
+		// The same update has been completed concurrently
+		return nil, appError.NewConflict("payment has already been paid")
+	}
+	return subscription, nil
+}
+
+func (u *UseCaseProfile) findPendingPayment(
+	ctx context.Context, c *profile.CheckoutPayment) (*profile.PaymentProfile, error) {
+	p, isExist, err := u.profileRepo.FindPaymentByPayload(ctx, c.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if !isExist {
+		return nil, appError.NewNotFound("payment not found")
+	}
+	if p.Status != profile.PaymentStatusPending {
+		return nil, appError.NewConflict(fmt.Sprintf("payment has already been %s", p.Status))
+	}
+	if p.Currency != c.Currency || p.Amount != c.TotalAmount {
+		return nil, appError.NewValidation("payment amount does not match the invoice")
+	}
+	return p, nil
+}
+
+func newPaymentPayload() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
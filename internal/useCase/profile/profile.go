@@ -2,9 +2,14 @@ package profile
 
 import (
 	"context"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/pagination"
 	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
 	"github.com/EvgeniyBudaev/love-server/internal/logger"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
 	"go.uber.org/zap"
+	"time"
 )
 
 type Store interface {
@@ -12,7 +17,12 @@ type Store interface {
 	Update(ctx context.Context, p *profile.Profile) (*profile.Profile, error)
 	UpdateLastOnline(ctx context.Context, profileID uint64) error
 	Delete(ctx context.Context, p *profile.Profile) (*profile.Profile, error)
-	SelectList(ctx context.Context, qp *profile.QueryParamsProfileList) (*profile.ResponseListProfile, error)
+	Restore(ctx context.Context, profileID uint64, deletedAfter time.Time, updatedAt time.Time) (bool, error)
+	SelectListDeletedBefore(ctx context.Context, moment time.Time) ([]*profile.DeletedProfile, error)
+	Erase(ctx context.Context, profileID uint64, erasedAt time.Time) (map[string]int64, error)
+	AddDeletionAudit(ctx context.Context, p *profile.DeletionAuditProfile) (*profile.DeletionAuditProfile, error)
+	SelectListCandidate(ctx context.Context, qp *profile.QueryParamsProfileList,
+		limit uint64) ([]*profile.CandidateProfile, error)
 	FindById(ctx context.Context, id uint64) (*profile.Profile, error)
 	FindBySessionID(ctx context.Context, sessionID string) (*profile.Profile, error)
 	FindByTelegramId(ctx context.Context, telegramID uint64) (*profile.Profile, error)
@@ -35,172 +45,365 @@ type Store interface {
 	UpdateImage(ctx context.Context, p *profile.ImageProfile) (*profile.ImageProfile, error)
 	FindImageById(ctx context.Context, imageID uint64) (*profile.ImageProfile, error)
 	SelectListPublicImage(ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error)
+	SelectListFirstPublicImage(ctx context.Context, profileIDs []uint64) (map[uint64]*profile.ImageProfile, error)
 	SelectListImage(ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error)
 	CheckIfCommonImageExists(ctx context.Context, profileID uint64, fileName string) (bool, uint64, error)
 	AddReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error)
 	UpdateReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error)
 	DeleteReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error)
 	FindReviewById(ctx context.Context, id uint64) (*profile.ResponseReviewProfile, error)
+	FindReviewByHumanID(ctx context.Context, profileID uint64, humanID uint64) (*profile.ReviewProfile, bool, error)
 	SelectReviewList(ctx context.Context, qp *profile.QueryParamsReviewList) (*profile.ResponseListReview, error)
+	SelectListReviewByStatus(ctx context.Context,
+		qp *profile.QueryParamsReviewModerationList) (*profile.ResponseListReviewModeration, error)
+	AddReviewReport(ctx context.Context, p *profile.ReviewReportProfile) (*profile.ReviewReportProfile, error)
 	AddLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error)
 	UpdateLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error)
 	DeleteLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error)
 	FindLikeByHumanID(ctx context.Context, profileID uint64, humanID uint64) (*profile.LikeProfile, bool, error)
 	FindLikeByID(ctx context.Context, id uint64) (*profile.LikeProfile, bool, error)
+	SelectListLike(
+		ctx context.Context, profileID uint64, qp *profile.QueryParamsLikeList) (*profile.ResponseListLike, error)
+	FindLikeStatsByProfileID(ctx context.Context, profileID uint64) (*profile.LikeStatsProfile, error)
+	AddSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error)
+	UpdateSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error)
+	FindLastPassSwipe(ctx context.Context, profileID uint64) (*profile.SwipeProfile, bool, error)
+	CountRewindTodayByProfileID(ctx context.Context, profileID uint64) (uint64, error)
+	ReserveActionTodayByProfileID(
+		ctx context.Context, action string, profileID uint64, limit uint64) (uint64, bool, error)
+	ReleaseActionTodayByProfileID(ctx context.Context, action string, profileID uint64) error
+	AddSubscription(ctx context.Context, p *profile.SubscriptionProfile) (*profile.SubscriptionProfile, error)
+	FindActiveSubscriptionByProfileID(
+		ctx context.Context, profileID uint64) (*profile.SubscriptionProfile, bool, error)
+	ExpireSubscriptions(ctx context.Context) (int64, error)
+	AddPayment(ctx context.Context, p *profile.PaymentProfile) (*profile.PaymentProfile, error)
+	CompletePayment(ctx context.Context, p *profile.PaymentProfile,
+		s *profile.SubscriptionProfile) (*profile.SubscriptionProfile, bool, error)
+	FindPaymentByPayload(ctx context.Context, payload string) (*profile.PaymentProfile, bool, error)
+	AddVisit(ctx context.Context, p *profile.VisitProfile) (*profile.VisitProfile, error)
+	SelectListVisit(
+		ctx context.Context, profileID uint64, qp *profile.QueryParamsVisitList) (*profile.ResponseListVisit, error)
+	DeleteVisitBefore(ctx context.Context, moment time.Time) (int64, error)
 	AddBlock(ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error)
 	UpdateBlock(ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error)
 	FindBlockByID(ctx context.Context, id uint64) (*profile.BlockedProfile, bool, error)
+	FindBlockByHumanID(ctx context.Context, profileID, blockedUserID uint64) (*profile.BlockedProfile, bool, error)
+	CheckIfBlockExists(ctx context.Context, profileID, humanID uint64) (bool, error)
+	SelectListBlock(
+		ctx context.Context, profileID uint64, qp *profile.QueryParamsBlockList) (*profile.ResponseListBlock, error)
 	AddComplaint(ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error)
 	UpdateComplaint(ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error)
 	FindComplaintByID(ctx context.Context, id uint64) (*profile.ComplaintProfile, bool, error)
 	SelectListComplaintByID(ctx context.Context, complaintUserID uint64) ([]*profile.ComplaintProfile, error)
+	SelectListLikeByProfileID(ctx context.Context, profileID uint64) ([]*profile.LikeProfile, error)
+	SelectListLikeByHumanID(ctx context.Context, profileID uint64) ([]*profile.LikeProfile, error)
+	SelectListBlockByProfileID(ctx context.Context, profileID uint64) ([]*profile.BlockedProfile, error)
+	SelectListComplaintByProfileID(ctx context.Context, profileID uint64) ([]*profile.ComplaintProfile, error)
+	SelectListReviewByProfileID(ctx context.Context, profileID uint64) ([]*profile.ReviewProfile, error)
+	AddExport(ctx context.Context, p *profile.ExportProfile) (*profile.ExportProfile, error)
+	UpdateExport(ctx context.Context, p *profile.ExportProfile) (*profile.ExportProfile, error)
+	FindExportByID(ctx context.Context, id uint64) (*profile.ExportProfile, bool, error)
+	FindPendingExportByProfileID(ctx context.Context, profileID uint64) (*profile.ExportProfile, bool, error)
+	SelectListExportByStatus(ctx context.Context, status string) ([]*profile.ExportProfile, error)
+	SelectListExportByProfileID(ctx context.Context, profileID uint64) ([]*profile.ExportProfile, error)
+	AddAgeVerification(
+		ctx context.Context, p *profile.AgeVerificationProfile) (*profile.AgeVerificationProfile, error)
+	UpdateAgeVerification(
+		ctx context.Context, p *profile.AgeVerificationProfile) (*profile.AgeVerificationProfile, error)
+	FindAgeVerificationByID(ctx context.Context, id uint64) (*profile.AgeVerificationProfile, bool, error)
+	FindPendingAgeVerificationByProfileID(
+		ctx context.Context, profileID uint64) (*profile.AgeVerificationProfile, bool, error)
+	SelectListAgeVerificationByStatus(ctx context.Context,
+		qp *profile.QueryParamsAgeVerificationList) (*profile.ResponseListAgeVerification, error)
+	AddSelfieVerification(
+		ctx context.Context, p *profile.SelfieVerificationProfile) (*profile.SelfieVerificationProfile, error)
+	UpdateSelfieVerification(
+		ctx context.Context, p *profile.SelfieVerificationProfile) (*profile.SelfieVerificationProfile, error)
+	FindSelfieVerificationByID(ctx context.Context, id uint64) (*profile.SelfieVerificationProfile, bool, error)
+	FindLastSelfieVerificationByProfileID(
+		ctx context.Context, profileID uint64) (*profile.SelfieVerificationProfile, bool, error)
+	SelectListSelfieVerificationByStatus(ctx context.Context,
+		qp *profile.QueryParamsSelfieModerationList) (*profile.ResponseListSelfieModeration, error)
+	SelectListSelfieVerificationByProfileID(
+		ctx context.Context, profileID uint64) ([]*profile.SelfieVerificationProfile, error)
 }
 
 type UseCaseProfile struct {
 	logger      logger.Logger
 	profileRepo Store
+	ranker      Ranker
+	entitlement EntitlementChecker
+	payment     PaymentProvider
+	quota       *QuotaService
+	location    *LocationPrivacy
+	review      ReviewFilter
+	selfie      SelfieComparer
 }
 
-func NewUseCaseProfile(l logger.Logger, pr Store) *UseCaseProfile {
+func NewUseCaseProfile(l logger.Logger, pr Store, rk Ranker, ec EntitlementChecker, pp PaymentProvider,
+	qs *QuotaService, lp *LocationPrivacy, rf ReviewFilter, sc SelfieComparer) *UseCaseProfile {
 	return &UseCaseProfile{
 		logger:      l,
 		profileRepo: pr,
+		ranker:      rk,
+		entitlement: ec,
+		payment:     pp,
+		quota:       qs,
+		location:    lp,
+		review:      rf,
+		selfie:      sc,
 	}
 }
 
 func (u *UseCaseProfile) Add(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.Add")
+	defer span.End()
+	if !profile.IsAdult(p.Birthday, time.Now().UTC()) {
+		message := fmt.Sprintf("the age must be at least %d", profile.MinAge)
+		return nil, appError.NewValidation("invalid profile", &appError.FieldError{Field: "birthday", Message: message})
+	}
 	response, err := u.profileRepo.Add(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func Add, method Add by path internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func Add, method Add", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) Update(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.Update")
+	defer span.End()
 	response, err := u.profileRepo.Update(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func Update, method Update by path internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func Update, method Update", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) UpdateLastOnline(ctx context.Context, profileID uint64) error {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateLastOnline")
+	defer span.End()
 	err := u.profileRepo.UpdateLastOnline(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func UpdateLastOnline, method UpdateLastOnline by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateLastOnline, method UpdateLastOnline", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
 func (u *UseCaseProfile) Delete(ctx context.Context, p *profile.Profile) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.Delete")
+	defer span.End()
 	response, err := u.profileRepo.Delete(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func Delete, method Delete by path internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func Delete, method Delete", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
+// Restore restores the deleted profile when profile.DeletionGracePeriod has not passed yet
+func (u *UseCaseProfile) Restore(ctx context.Context, profileID uint64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.Restore")
+	defer span.End()
+	now := time.Now().UTC()
+	response, err := u.profileRepo.Restore(ctx, profileID, now.Add(-profile.DeletionGracePeriod), now)
+	if err != nil {
+		u.logger.With(ctx).Error("error func Restore, method Restore", zap.Error(err))
+		return false, err
+	}
+	return response, nil
+}
+
 func (u *UseCaseProfile) SelectList(
 	ctx context.Context, qp *profile.QueryParamsProfileList) (*profile.ResponseListProfile, error) {
-	response, err := u.profileRepo.SelectList(ctx, qp)
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectList")
+	defer span.End()
+	viewer, err := u.profileRepo.FindBySessionID(ctx, qp.SessionID)
 	if err != nil {
-		u.logger.Debug("error func SelectList, method SelectList by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func SelectList, method FindBySessionID", zap.Error(err))
 		return nil, err
 	}
-	return response, nil
+	likeStats, err := u.profileRepo.FindLikeStatsByProfileID(ctx, viewer.ID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectList, method FindLikeStatsByProfileID", zap.Error(err))
+		return nil, err
+	}
+	candidates, err := u.profileRepo.SelectListCandidate(ctx, qp, candidatePoolSize)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectList, method SelectListCandidate", zap.Error(err))
+		return nil, err
+	}
+	radius, err := profile.GetFeedRadius(qp.Distance)
+	if err != nil {
+		u.logger.With(ctx).Warn("error func SelectList, method GetFeedRadius", zap.Error(err))
+		return nil, appError.NewValidation("distance is invalid",
+			&appError.FieldError{Field: "distance", Message: "distance is invalid"})
+	}
+	// The feed is filtered and ordered on the noisy distance the viewer is shown, so the exact distance can't be
+	// found by changing the radius. The profiles which hide the distance are ranked as if they were at the radius.
+	inRadius := make([]*profile.CandidateProfile, 0, len(candidates))
+	for _, c := range candidates {
+		c.Distance = u.location.NoisyDistance(c.Profile.ID, viewer.ID, c.Distance)
+		if c.Distance > radius {
+			continue
+		}
+		if !c.Profile.IsShowDistance {
+			c.Distance = radius
+		}
+		inRadius = append(inRadius, c)
+	}
+	rv := &profile.RankViewer{
+		Profile:   viewer,
+		LikeStats: likeStats,
+		RankedAt:  time.Now().UTC(),
+	}
+	// The pages are sliced from the same ranked pool, so a profile is shown on one page only
+	ranked := u.ranker.Rank(rv, inRadius)
+	totalItems := uint64(len(ranked))
+	start, end := pagination.GetSliceBounds(qp.Page, qp.Size, totalItems)
+	profileIDs := make([]uint64, 0, end-start)
+	for _, c := range ranked[start:end] {
+		profileIDs = append(profileIDs, c.Profile.ID)
+	}
+	images, err := u.profileRepo.SelectListFirstPublicImage(ctx, profileIDs)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectList, method SelectListFirstPublicImage", zap.Error(err))
+		return nil, err
+	}
+	list := make([]*profile.ContentListProfile, 0, end-start)
+	for _, c := range ranked[start:end] {
+		lp := profile.ContentListProfile{
+			ID:         c.Profile.ID,
+			IsOnline:   false,
+			IsVerified: c.Profile.IsVerified,
+			LastOnline: nil,
+			Image:      nil,
+			Navigator:  &profile.ResponseNavigatorProfile{},
+		}
+		if c.Profile.IsShowDistance {
+			distance := c.Distance
+			lp.Navigator.Distance = &distance
+		}
+		// The online status of invisible profiles is hidden
+		if !c.Profile.IsInvisible {
+			lastOnline := c.Profile.LastOnline
+			lp.LastOnline = &lastOnline
+			lp.IsOnline = time.Since(lastOnline).Minutes() < 5
+		}
+		if image, ok := images[c.Profile.ID]; ok {
+			i := profile.ResponseImageProfile{
+				Url: image.Url,
+			}
+			lp.Image = &i
+		}
+		list = append(list, &lp)
+	}
+	paging := pagination.GetPagination(qp.Size, qp.Page, totalItems)
+	response := profile.ResponseListProfile{
+		Pagination: paging,
+		Content:    list,
+	}
+	return &response, nil
 }
 
 func (u *UseCaseProfile) FindById(ctx context.Context, id uint64) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindById")
+	defer span.End()
 	response, err := u.profileRepo.FindById(ctx, id)
 	if err != nil {
-		u.logger.Debug("error func FindById, method FindById by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func FindById, method FindById", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindBySessionID(ctx context.Context, sessionID string) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindBySessionID")
+	defer span.End()
 	response, err := u.profileRepo.FindBySessionID(ctx, sessionID)
 	if err != nil {
-		u.logger.Debug("error func FindBySessionID, method FindBySessionID by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func FindBySessionID, method FindBySessionID", zap.Error(err))
 		return nil, err
 	}
+	// The session of the request is of this profile, it is logged with the request from now on
+	logger.SetProfileID(ctx, response.ID)
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindByTelegramId(ctx context.Context, telegramID uint64) (*profile.Profile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindByTelegramId")
+	defer span.End()
 	response, err := u.profileRepo.FindByTelegramId(ctx, telegramID)
 	if err != nil {
-		u.logger.Debug("error func FindByTelegramId, methodFindByTelegramId by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindByTelegramId, methodFindByTelegramId", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) AddImage(ctx context.Context, i *profile.ImageProfile) (*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddImage")
+	defer span.End()
 	response, err := u.profileRepo.AddImage(ctx, i)
 	if err != nil {
-		u.logger.Debug("error func AddImage, method AddImage by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func AddImage, method AddImage", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) UpdateImage(ctx context.Context, i *profile.ImageProfile) (*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateImage")
+	defer span.End()
 	response, err := u.profileRepo.UpdateImage(ctx, i)
 	if err != nil {
-		u.logger.Debug("error func UpdateImage, method UpdateImage by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateImage, method UpdateImage", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) DeleteImage(ctx context.Context, i *profile.ImageProfile) (*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteImage")
+	defer span.End()
 	response, err := u.profileRepo.DeleteImage(ctx, i)
 	if err != nil {
-		u.logger.Debug("error func DeleteImage, method DeleteImage by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteImage, method DeleteImage", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindImageById(ctx context.Context, imageID uint64) (*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindImageById")
+	defer span.End()
 	response, err := u.profileRepo.FindImageById(ctx, imageID)
 	if err != nil {
-		u.logger.Debug("error func FindImageById, method FindImageById by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func FindImageById, method FindImageById", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) SelectListPublicImage(ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListPublicImage")
+	defer span.End()
 	response, err := u.profileRepo.SelectListPublicImage(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func SelectListPublicImage, method SelectListPublicImage by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func SelectListPublicImage, method SelectListPublicImage", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) SelectListImage(ctx context.Context, profileID uint64) ([]*profile.ImageProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListImage")
+	defer span.End()
 	response, err := u.profileRepo.SelectListImage(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func SelectListImage, method SelectListImage by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func SelectListImage, method SelectListImage", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -208,15 +411,18 @@ func (u *UseCaseProfile) SelectListImage(ctx context.Context, profileID uint64)
 
 func (u *UseCaseProfile) CheckIfCommonImageExists(
 	ctx context.Context, profileID uint64, fileName string) (bool, uint64, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CheckIfCommonImageExists")
+	defer span.End()
 	return u.profileRepo.CheckIfCommonImageExists(ctx, profileID, fileName)
 }
 
 func (u *UseCaseProfile) AddTelegram(
 	ctx context.Context, t *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddTelegram")
+	defer span.End()
 	response, err := u.profileRepo.AddTelegram(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func AddTelegram, method AddTelegram by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func AddTelegram, method AddTelegram", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -224,10 +430,11 @@ func (u *UseCaseProfile) AddTelegram(
 
 func (u *UseCaseProfile) UpdateTelegram(
 	ctx context.Context, t *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateTelegram")
+	defer span.End()
 	response, err := u.profileRepo.UpdateTelegram(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func UpdateTelegram, method UpdateTelegram by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateTelegram, method UpdateTelegram", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -235,10 +442,11 @@ func (u *UseCaseProfile) UpdateTelegram(
 
 func (u *UseCaseProfile) DeleteTelegram(
 	ctx context.Context, t *profile.TelegramProfile) (*profile.TelegramProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteTelegram")
+	defer span.End()
 	response, err := u.profileRepo.DeleteTelegram(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func DeleteTelegram, method DeleteTelegram by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteTelegram, method DeleteTelegram", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -246,10 +454,11 @@ func (u *UseCaseProfile) DeleteTelegram(
 
 func (u *UseCaseProfile) FindTelegramByProfileID(
 	ctx context.Context, profileID uint64) (*profile.TelegramProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindTelegramByProfileID")
+	defer span.End()
 	response, err := u.profileRepo.FindTelegramByProfileID(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func FindTelegramByProfileID, method FindTelegramByProfileID by path "+
-			"internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindTelegramByProfileID, method FindTelegramByProfileID", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -257,10 +466,11 @@ func (u *UseCaseProfile) FindTelegramByProfileID(
 
 func (u *UseCaseProfile) AddNavigator(
 	ctx context.Context, n *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddNavigator")
+	defer span.End()
 	response, err := u.profileRepo.AddNavigator(ctx, n)
 	if err != nil {
-		u.logger.Debug("error func AddNavigator, method AddNavigator by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func AddNavigator, method AddNavigator", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -268,10 +478,25 @@ func (u *UseCaseProfile) AddNavigator(
 
 func (u *UseCaseProfile) UpdateNavigator(
 	ctx context.Context, n *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateNavigator")
+	defer span.End()
+	current, err := u.profileRepo.FindNavigatorByProfileID(ctx, n.ProfileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func UpdateNavigator, method FindNavigatorByProfileID", zap.Error(err))
+		return nil, err
+	}
+	// The short moves keep the current location, so moving by small steps is counted as the jumps too
+	if u.location.IsShortMove(current, n) {
+		return current, nil
+	}
+	// The profile which keeps jumping stays at its current location
+	if !u.location.IsMoveAllowed(current, n, time.Now().UTC()) {
+		u.logger.With(ctx).Warn("func UpdateNavigator, location jump is refused", zap.Uint64("profileId", n.ProfileID))
+		return nil, appError.NewConflict("location jump limit is reached")
+	}
 	response, err := u.profileRepo.UpdateNavigator(ctx, n)
 	if err != nil {
-		u.logger.Debug("error func UpdateTNavigator, method UpdateNavigator by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateTNavigator, method UpdateNavigator", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -279,10 +504,11 @@ func (u *UseCaseProfile) UpdateNavigator(
 
 func (u *UseCaseProfile) DeleteNavigator(
 	ctx context.Context, n *profile.NavigatorProfile) (*profile.NavigatorProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteNavigator")
+	defer span.End()
 	response, err := u.profileRepo.DeleteNavigator(ctx, n)
 	if err != nil {
-		u.logger.Debug("error func DeleteNavigator, method DeleteNavigator by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteNavigator, method DeleteNavigator", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -290,10 +516,11 @@ func (u *UseCaseProfile) DeleteNavigator(
 
 func (u *UseCaseProfile) FindNavigatorByProfileID(
 	ctx context.Context, profileID uint64) (*profile.NavigatorProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindNavigatorByProfileID")
+	defer span.End()
 	response, err := u.profileRepo.FindNavigatorByProfileID(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func FindNavigatorByProfileID, method FindNavigatorByProfileID by path "+
-			"internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindNavigatorByProfileID, method FindNavigatorByProfileID", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -301,21 +528,33 @@ func (u *UseCaseProfile) FindNavigatorByProfileID(
 
 func (u *UseCaseProfile) FindNavigatorByProfileIDAndViewerID(
 	ctx context.Context, profileID uint64, viewerID uint64) (*profile.ResponseNavigatorProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindNavigatorByProfileIDAndViewerID")
+	defer span.End()
 	response, err := u.profileRepo.FindNavigatorByProfileIDAndViewerID(ctx, profileID, viewerID)
 	if err != nil {
-		u.logger.Debug("error func FindNavigatorByProfileIDAndViewerId, method FindNavigatorByProfileIDAndViewerId"+
-			" by path internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerId,"+
+			" method FindNavigatorByProfileIDAndViewerId", zap.Error(err))
+		return nil, err
+	}
+	p, err := u.profileRepo.FindById(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindNavigatorByProfileIDAndViewerId, method FindById"+
+			"", zap.Error(err))
 		return nil, err
 	}
+	if response.Distance != nil {
+		response.Distance = u.location.Distance(p, viewerID, *response.Distance)
+	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) AddFilter(
 	ctx context.Context, t *profile.FilterProfile) (*profile.FilterProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddFilter")
+	defer span.End()
 	response, err := u.profileRepo.AddFilter(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func AddFilter, method AddFilter by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func AddFilter, method AddFilter", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -323,10 +562,11 @@ func (u *UseCaseProfile) AddFilter(
 
 func (u *UseCaseProfile) UpdateFilter(
 	ctx context.Context, t *profile.FilterProfile) (*profile.FilterProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateFilter")
+	defer span.End()
 	response, err := u.profileRepo.UpdateFilter(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func UpdateFilter, method UpdateFilter by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateFilter, method UpdateFilter", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -334,101 +574,155 @@ func (u *UseCaseProfile) UpdateFilter(
 
 func (u *UseCaseProfile) DeleteFilter(
 	ctx context.Context, t *profile.FilterProfile) (*profile.FilterProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteFilter")
+	defer span.End()
 	response, err := u.profileRepo.DeleteFilter(ctx, t)
 	if err != nil {
-		u.logger.Debug("error func DeleteFilter, method DeleteFilter by path internal/useCase/profile/profile.go",
-			zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteFilter, method DeleteFilter", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindFilterByProfileID(ctx context.Context, profileID uint64) (*profile.FilterProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindFilterByProfileID")
+	defer span.End()
 	response, err := u.profileRepo.FindFilterByProfileID(ctx, profileID)
 	if err != nil {
-		u.logger.Debug("error func FindFilterByProfileID, method FindFilterByProfileID by path "+
-			"internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindFilterByProfileID, method FindFilterByProfileID", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
+// GetReviewStatus returns the status of the new or edited review, the suspicious reviews wait for a moderator
+func (u *UseCaseProfile) GetReviewStatus(message string) string {
+	if u.review != nil && u.review.IsSuspicious(message) {
+		return profile.ReviewStatusPending
+	}
+	return profile.ReviewStatusPublished
+}
+
 func (u *UseCaseProfile) AddReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddReview")
+	defer span.End()
 	response, err := u.profileRepo.AddReview(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func AddReview, method AddReview by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func AddReview, method AddReview", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) UpdateReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateReview")
+	defer span.End()
 	response, err := u.profileRepo.UpdateReview(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func UpdateReview, method UpdateReview by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateReview, method UpdateReview", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) DeleteReview(ctx context.Context, p *profile.ReviewProfile) (*profile.ReviewProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteReview")
+	defer span.End()
 	response, err := u.profileRepo.DeleteReview(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func DeleteReview, method DeleteReview by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteReview, method DeleteReview", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindReviewById(ctx context.Context, id uint64) (*profile.ResponseReviewProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindReviewById")
+	defer span.End()
 	response, err := u.profileRepo.FindReviewById(ctx, id)
 	if err != nil {
-		u.logger.Debug("error func FindReviewById, method FindReviewById by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindReviewById, method FindReviewById", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
+func (u *UseCaseProfile) FindReviewByHumanID(
+	ctx context.Context, profileID uint64, humanID uint64) (*profile.ReviewProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindReviewByHumanID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindReviewByHumanID(ctx, profileID, humanID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindReviewByHumanID, method FindReviewByHumanID", zap.Error(err))
+		return nil, false, err
+	}
+	return response, isExist, nil
+}
+
 func (u *UseCaseProfile) SelectReviewList(
 	ctx context.Context, qp *profile.QueryParamsReviewList) (*profile.ResponseListReview, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectReviewList")
+	defer span.End()
 	response, err := u.profileRepo.SelectReviewList(ctx, qp)
 	if err != nil {
-		u.logger.Debug("error func SelectReviewList, method SelectReviewList by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func SelectReviewList, method SelectReviewList", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) SelectListReviewByStatus(ctx context.Context,
+	qp *profile.QueryParamsReviewModerationList) (*profile.ResponseListReviewModeration, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListReviewByStatus")
+	defer span.End()
+	response, err := u.profileRepo.SelectListReviewByStatus(ctx, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListReviewByStatus, method SelectListReviewByStatus", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) AddReviewReport(
+	ctx context.Context, p *profile.ReviewReportProfile) (*profile.ReviewReportProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddReviewReport")
+	defer span.End()
+	response, err := u.profileRepo.AddReviewReport(ctx, p)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddReviewReport, method AddReviewReport", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) AddLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddLike")
+	defer span.End()
 	response, err := u.profileRepo.AddLike(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func AddLike, method AddLike by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func AddLike, method AddLike", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) UpdateLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateLike")
+	defer span.End()
 	response, err := u.profileRepo.UpdateLike(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func UpdateLike, method UpdateLike by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateLike, method UpdateLike", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) DeleteLike(ctx context.Context, p *profile.LikeProfile) (*profile.LikeProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteLike")
+	defer span.End()
 	response, err := u.profileRepo.DeleteLike(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func DeleteLike, method DeleteLike by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func DeleteLike, method DeleteLike", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -436,31 +730,222 @@ func (u *UseCaseProfile) DeleteLike(ctx context.Context, p *profile.LikeProfile)
 
 func (u *UseCaseProfile) FindLikeByHumanID(
 	ctx context.Context, profileID uint64, humanID uint64) (*profile.LikeProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindLikeByHumanID")
+	defer span.End()
 	response, isExist, err := u.profileRepo.FindLikeByHumanID(ctx, profileID, humanID)
 	if err != nil {
-		u.logger.Debug("error func FindLikeByHumanID, method FindLikeByHumanID by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindLikeByHumanID, method FindLikeByHumanID", zap.Error(err))
 		return nil, isExist, err
 	}
 	return response, isExist, nil
 }
 
 func (u *UseCaseProfile) FindLikeByID(ctx context.Context, id uint64) (*profile.LikeProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindLikeByID")
+	defer span.End()
 	response, isExist, err := u.profileRepo.FindLikeByID(ctx, id)
 	if err != nil {
-		u.logger.Debug("error func FindLikeByID, method FindLikeByID by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindLikeByID, method FindLikeByID", zap.Error(err))
+		return nil, isExist, err
+	}
+	return response, isExist, nil
+}
+
+func (u *UseCaseProfile) SelectListLike(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsLikeList) (*profile.ResponseListLike, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListLike")
+	defer span.End()
+	response, err := u.profileRepo.SelectListLike(ctx, profileID, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListLike, method SelectListLike", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) AddSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddSwipe")
+	defer span.End()
+	response, err := u.profileRepo.AddSwipe(ctx, p)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddSwipe, method AddSwipe", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) UpdateSwipe(ctx context.Context, p *profile.SwipeProfile) (*profile.SwipeProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateSwipe")
+	defer span.End()
+	response, err := u.profileRepo.UpdateSwipe(ctx, p)
+	if err != nil {
+		u.logger.With(ctx).Error("error func UpdateSwipe, method UpdateSwipe", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) FindLastPassSwipe(
+	ctx context.Context, profileID uint64) (*profile.SwipeProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindLastPassSwipe")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindLastPassSwipe(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindLastPassSwipe, method FindLastPassSwipe", zap.Error(err))
 		return nil, isExist, err
 	}
 	return response, isExist, nil
 }
 
+func (u *UseCaseProfile) CountRewindTodayByProfileID(ctx context.Context, profileID uint64) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CountRewindTodayByProfileID")
+	defer span.End()
+	response, err := u.profileRepo.CountRewindTodayByProfileID(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CountRewindTodayByProfileID, method CountRewindTodayByProfileID",
+			zap.Error(err))
+		return 0, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) ReserveQuota(
+	ctx context.Context, p *profile.Profile, action string) (*profile.QuotaProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ReserveQuota")
+	defer span.End()
+	response, isReserved, err := u.quota.Reserve(ctx, p, action)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ReserveQuota, method Reserve", zap.Error(err))
+		return nil, false, err
+	}
+	return response, isReserved, nil
+}
+
+func (u *UseCaseProfile) ReleaseQuota(ctx context.Context, p *profile.Profile, action string) error {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ReleaseQuota")
+	defer span.End()
+	if err := u.quota.Release(ctx, p, action); err != nil {
+		u.logger.With(ctx).Error("error func ReleaseQuota, method Release", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *UseCaseProfile) IsEntitled(p *profile.Profile, feature string) bool {
+	return u.entitlement.IsEntitled(p, feature)
+}
+
+// ActivateSubscription adds a subscription for the profile. If the profile already has an active subscription,
+// the new period starts when the active one ends.
+func (u *UseCaseProfile) ActivateSubscription(
+	ctx context.Context, profileID uint64, plan string, period string) (*profile.SubscriptionProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ActivateSubscription")
+	defer span.End()
+	subscriptionDto, err := u.newSubscription(ctx, profileID, plan, period)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ActivateSubscription, method newSubscription", zap.Error(err))
+		return nil, err
+	}
+	response, err := u.profileRepo.AddSubscription(ctx, subscriptionDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ActivateSubscription, method AddSubscription", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+// newSubscription returns the subscription for the period which follows the active subscription of the profile
+func (u *UseCaseProfile) newSubscription(
+	ctx context.Context, profileID uint64, plan string, period string) (*profile.SubscriptionProfile, error) {
+	startedAt := time.Now().UTC()
+	s, isExist, err := u.profileRepo.FindActiveSubscriptionByProfileID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if isExist && s.EndedAt.After(startedAt) {
+		startedAt = s.EndedAt
+	}
+	endedAt, err := profile.GetSubscriptionEndedAt(startedAt, period)
+	if err != nil {
+		return nil, err
+	}
+	return &profile.SubscriptionProfile{
+		ProfileID: profileID,
+		Plan:      plan,
+		Period:    period,
+		Status:    profile.SubscriptionStatusActive,
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (u *UseCaseProfile) FindActiveSubscriptionByProfileID(
+	ctx context.Context, profileID uint64) (*profile.SubscriptionProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindActiveSubscriptionByProfileID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindActiveSubscriptionByProfileID(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindActiveSubscriptionByProfileID,"+
+			" method FindActiveSubscriptionByProfileID", zap.Error(err))
+		return nil, false, err
+	}
+	return response, isExist, nil
+}
+
+func (u *UseCaseProfile) ExpireSubscriptions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ExpireSubscriptions")
+	defer span.End()
+	response, err := u.profileRepo.ExpireSubscriptions(ctx)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ExpireSubscriptions, method ExpireSubscriptions", zap.Error(err))
+		return 0, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) AddVisit(ctx context.Context, p *profile.VisitProfile) (*profile.VisitProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddVisit")
+	defer span.End()
+	response, err := u.profileRepo.AddVisit(ctx, p)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddVisit, method AddVisit", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) SelectListVisit(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsVisitList) (*profile.ResponseListVisit, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListVisit")
+	defer span.End()
+	response, err := u.profileRepo.SelectListVisit(ctx, profileID, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListVisit, method SelectListVisit", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) DeleteVisitBefore(ctx context.Context, moment time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.DeleteVisitBefore")
+	defer span.End()
+	response, err := u.profileRepo.DeleteVisitBefore(ctx, moment)
+	if err != nil {
+		u.logger.With(ctx).Error("error func DeleteVisitBefore, method DeleteVisitBefore", zap.Error(err))
+		return 0, err
+	}
+	return response, nil
+}
+
 func (u *UseCaseProfile) AddBlock(
 	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddBlock")
+	defer span.End()
 	response, err := u.profileRepo.AddBlock(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func AddBlock, method AddBlock by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func AddBlock, method AddBlock", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -468,31 +953,69 @@ func (u *UseCaseProfile) AddBlock(
 
 func (u *UseCaseProfile) UpdateBlock(
 	ctx context.Context, p *profile.BlockedProfile) (*profile.BlockedProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateBlock")
+	defer span.End()
 	response, err := u.profileRepo.UpdateBlock(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func UpdateBlock, method UpdateBlock by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateBlock, method UpdateBlock", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindBlockByID(ctx context.Context, id uint64) (*profile.BlockedProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindBlockByID")
+	defer span.End()
 	response, isExist, err := u.profileRepo.FindBlockByID(ctx, id)
 	if err != nil {
-		u.logger.Debug("error func FindBlockByID, method FindBlockByID by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindBlockByID, method FindBlockByID", zap.Error(err))
+		return nil, isExist, err
+	}
+	return response, isExist, nil
+}
+
+func (u *UseCaseProfile) FindBlockByHumanID(
+	ctx context.Context, profileID, blockedUserID uint64) (*profile.BlockedProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindBlockByHumanID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindBlockByHumanID(ctx, profileID, blockedUserID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindBlockByHumanID, method FindBlockByHumanID", zap.Error(err))
 		return nil, isExist, err
 	}
 	return response, isExist, nil
 }
 
+func (u *UseCaseProfile) CheckIfBlockExists(ctx context.Context, profileID, humanID uint64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.CheckIfBlockExists")
+	defer span.End()
+	isExist, err := u.profileRepo.CheckIfBlockExists(ctx, profileID, humanID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func CheckIfBlockExists, method CheckIfBlockExists", zap.Error(err))
+		return false, err
+	}
+	return isExist, nil
+}
+
+func (u *UseCaseProfile) SelectListBlock(
+	ctx context.Context, profileID uint64, qp *profile.QueryParamsBlockList) (*profile.ResponseListBlock, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListBlock")
+	defer span.End()
+	response, err := u.profileRepo.SelectListBlock(ctx, profileID, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListBlock, method SelectListBlock", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
 func (u *UseCaseProfile) AddComplaint(
 	ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddComplaint")
+	defer span.End()
 	response, err := u.profileRepo.AddComplaint(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func AddComplaint, method AddComplaint by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func AddComplaint, method AddComplaint", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -500,20 +1023,22 @@ func (u *UseCaseProfile) AddComplaint(
 
 func (u *UseCaseProfile) UpdateComplaint(
 	ctx context.Context, p *profile.ComplaintProfile) (*profile.ComplaintProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.UpdateComplaint")
+	defer span.End()
 	response, err := u.profileRepo.UpdateComplaint(ctx, p)
 	if err != nil {
-		u.logger.Debug("error func UpdateComplaint, method UpdateComplaint by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func UpdateComplaint, method UpdateComplaint", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
 }
 
 func (u *UseCaseProfile) FindComplaintByID(ctx context.Context, id uint64) (*profile.ComplaintProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindComplaintByID")
+	defer span.End()
 	response, isExist, err := u.profileRepo.FindComplaintByID(ctx, id)
 	if err != nil {
-		u.logger.Debug("error func FindComplaintByID, method FindComplaintByID by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func FindComplaintByID, method FindComplaintByID", zap.Error(err))
 		return nil, isExist, err
 	}
 	return response, isExist, nil
@@ -521,10 +1046,11 @@ func (u *UseCaseProfile) FindComplaintByID(ctx context.Context, id uint64) (*pro
 
 func (u *UseCaseProfile) SelectListComplaintByID(
 	ctx context.Context, complaintUserID uint64) ([]*profile.ComplaintProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListComplaintByID")
+	defer span.End()
 	response, err := u.profileRepo.SelectListComplaintByID(ctx, complaintUserID)
 	if err != nil {
-		u.logger.Debug("error func SelectListComplaintByID, method SelectListComplaintByID by path"+
-			" internal/useCase/profile/profile.go", zap.Error(err))
+		u.logger.With(ctx).Error("error func SelectListComplaintByID, method SelectListComplaintByID", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -0,0 +1,99 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"time"
+)
+
+// QuotaLimit is the number of actions per day. PremiumLimit applies to the profiles entitled to
+// profile.FeaturePremiumLimits, the limit is lifted for the profiles entitled to UnlimitedFeature.
+type QuotaLimit struct {
+	Limit            uint64
+	PremiumLimit     uint64
+	UnlimitedFeature string
+}
+
+func DefaultQuotaLimits() map[string]QuotaLimit {
+	return map[string]QuotaLimit{
+		profile.QuotaActionLike: {
+			Limit:            20,
+			PremiumLimit:     20,
+			UnlimitedFeature: profile.FeatureUnlimitedLikes,
+		},
+		profile.QuotaActionComplaint: {
+			Limit:        5,
+			PremiumLimit: 5,
+		},
+		profile.QuotaActionReview: {
+			Limit:        1,
+			PremiumLimit: 3,
+		},
+	}
+}
+
+type QuotaCounter interface {
+	ReserveActionTodayByProfileID(
+		ctx context.Context, action string, profileID uint64, limit uint64) (uint64, bool, error)
+	ReleaseActionTodayByProfileID(ctx context.Context, action string, profileID uint64) error
+}
+
+// QuotaService counts the actions which the profile has performed today against the daily limits
+type QuotaService struct {
+	counter     QuotaCounter
+	entitlement EntitlementChecker
+	limits      map[string]QuotaLimit
+}
+
+func NewQuotaService(c QuotaCounter, ec EntitlementChecker, limits map[string]QuotaLimit) *QuotaService {
+	return &QuotaService{counter: c, entitlement: ec, limits: limits}
+}
+
+// Reserve counts the action against the quota of today and reports whether the profile may perform it. The action
+// is counted before it is performed, so that the concurrent requests of the profile can't go beyond the limit.
+func (s *QuotaService) Reserve(
+	ctx context.Context, p *profile.Profile, action string) (*profile.QuotaProfile, bool, error) {
+	limit, ok := s.limits[action]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown quota action: %s", action)
+	}
+	now := time.Now().UTC()
+	quota := profile.QuotaProfile{
+		Action:  action,
+		Limit:   limit.Limit,
+		ResetAt: time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC),
+	}
+	if s.isUnlimited(p, limit) {
+		quota.IsUnlimited = true
+		return &quota, true, nil
+	}
+	if s.entitlement.IsEntitled(p, profile.FeaturePremiumLimits) {
+		quota.Limit = limit.PremiumLimit
+	}
+	used, isReserved, err := s.counter.ReserveActionTodayByProfileID(ctx, action, p.ID, quota.Limit)
+	if err != nil {
+		return nil, false, err
+	}
+	quota.Used = used
+	if used < quota.Limit {
+		quota.Remaining = quota.Limit - used
+	}
+	return &quota, isReserved, nil
+}
+
+// Release gives back the reserved action when it has not been performed
+func (s *QuotaService) Release(ctx context.Context, p *profile.Profile, action string) error {
+	limit, ok := s.limits[action]
+	if !ok {
+		return fmt.Errorf("unknown quota action: %s", action)
+	}
+	if s.isUnlimited(p, limit) {
+		return nil
+	}
+	return s.counter.ReleaseActionTodayByProfileID(ctx, action, p.ID)
+}
+
+func (s *QuotaService) isUnlimited(p *profile.Profile, limit QuotaLimit) bool {
+	return limit.UnlimitedFeature != "" && s.entitlement.IsEntitled(p, limit.UnlimitedFeature)
+}
@@ -0,0 +1,181 @@
+package profile
+
+import (
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	RankerWeighted = "weighted"
+	RankerShuffle  = "shuffle"
+)
+
+// Ranker orders the feed candidates for the viewer, the first candidate is shown first
+type Ranker interface {
+	Rank(viewer *profile.RankViewer, candidates []*profile.CandidateProfile) []*profile.CandidateProfile
+}
+
+// NewRanker returns the ranker by its name, the weighted ranker is used by default
+func NewRanker(name string, seed int64) Ranker {
+	if name == RankerShuffle {
+		return NewShuffleRanker(seed)
+	}
+	return NewWeightedRanker(DefaultRankWeights())
+}
+
+type RankWeights struct {
+	Distance     float64 `json:"distance"`
+	Recency      float64 `json:"recency"`
+	Completeness float64 `json:"completeness"`
+	Images       float64 `json:"images"`
+	LikeBack     float64 `json:"likeBack"`
+	Affinity     float64 `json:"affinity"`
+}
+
+func DefaultRankWeights() RankWeights {
+	return RankWeights{
+		Distance:     0.3,
+		Recency:      0.2,
+		Completeness: 0.1,
+		Images:       0.1,
+		LikeBack:     0.2,
+		Affinity:     0.1,
+	}
+}
+
+const (
+	// rankDistanceScale is the distance in meters at which the distance score halves
+	rankDistanceScale = 10000
+	// rankRecencyScale is the offline time at which the recency score halves
+	rankRecencyScale = 24 * time.Hour
+	// rankImagesLimit is the number of images that gives the maximum images score
+	rankImagesLimit = 5
+	// rankAgeScale is the age difference in years at which the affinity score halves
+	rankAgeScale = 5
+	// candidatePoolSize is the number of the candidates which are ranked for the feed, the pool does not depend on
+	// the page, so the pages do not overlap
+	candidatePoolSize = 500
+)
+
+// WeightedRanker scores every candidate as a weighted sum of signals normalized to [0, 1].
+// Candidates with equal scores are ordered by distance, last online and id, so the result is deterministic.
+type WeightedRanker struct {
+	weights RankWeights
+}
+
+func NewWeightedRanker(w RankWeights) *WeightedRanker {
+	return &WeightedRanker{weights: w}
+}
+
+func (r *WeightedRanker) Rank(
+	viewer *profile.RankViewer, candidates []*profile.CandidateProfile) []*profile.CandidateProfile {
+	for _, c := range candidates {
+		c.Score = r.weights.Distance*scoreDistance(c) +
+			r.weights.Recency*scoreRecency(viewer, c) +
+			r.weights.Completeness*scoreCompleteness(c) +
+			r.weights.Images*scoreImages(c) +
+			r.weights.LikeBack*scoreLikeBack(c) +
+			r.weights.Affinity*scoreAffinity(viewer, c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if !a.Profile.LastOnline.Equal(b.Profile.LastOnline) {
+			return a.Profile.LastOnline.After(b.Profile.LastOnline)
+		}
+		return a.Profile.ID < b.Profile.ID
+	})
+	return candidates
+}
+
+// ShuffleRanker orders candidates randomly. The order depends only on the seed and the viewer,
+// so that the pages of the same feed do not overlap.
+type ShuffleRanker struct {
+	seed int64
+}
+
+func NewShuffleRanker(seed int64) *ShuffleRanker {
+	return &ShuffleRanker{seed: seed}
+}
+
+func (r *ShuffleRanker) Rank(
+	viewer *profile.RankViewer, candidates []*profile.CandidateProfile) []*profile.CandidateProfile {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Profile.ID < candidates[j].Profile.ID
+	})
+	seed := r.seed
+	if viewer != nil && viewer.Profile != nil {
+		seed ^= int64(viewer.Profile.ID)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	rnd.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for i, c := range candidates {
+		c.Score = float64(len(candidates)-i) / float64(len(candidates))
+	}
+	return candidates
+}
+
+func scoreDistance(c *profile.CandidateProfile) float64 {
+	return rankDistanceScale / (rankDistanceScale + math.Max(c.Distance, 0))
+}
+
+func scoreRecency(viewer *profile.RankViewer, c *profile.CandidateProfile) float64 {
+	offline := viewer.RankedAt.Sub(c.Profile.LastOnline)
+	if offline < 0 {
+		offline = 0
+	}
+	return float64(rankRecencyScale) / float64(rankRecencyScale+offline)
+}
+
+func scoreCompleteness(c *profile.CandidateProfile) float64 {
+	p := c.Profile
+	fields := []bool{
+		p.DisplayName != "",
+		p.Description != "",
+		p.Location != "",
+		p.Gender != "",
+		!p.Birthday.IsZero(),
+		p.Height > 0,
+		p.Weight > 0,
+	}
+	filled := 0
+	for _, isFilled := range fields {
+		if isFilled {
+			filled++
+		}
+	}
+	return float64(filled) / float64(len(fields))
+}
+
+func scoreImages(c *profile.CandidateProfile) float64 {
+	return math.Min(float64(c.CountImages), rankImagesLimit) / rankImagesLimit
+}
+
+// scoreLikeBack estimates the probability that the candidate likes the viewer back.
+// The share of returned likes is smoothed, so that new profiles get 0.5.
+func scoreLikeBack(c *profile.CandidateProfile) float64 {
+	if c.IsLikedViewer {
+		return 1
+	}
+	return (float64(c.CountLikesReturned) + 1) / (float64(c.CountLikesReceived) + 2)
+}
+
+// scoreAffinity compares the candidate with the profiles the viewer liked before
+func scoreAffinity(viewer *profile.RankViewer, c *profile.CandidateProfile) float64 {
+	if viewer.LikeStats == nil || viewer.LikeStats.CountLiked == 0 {
+		return 0.5
+	}
+	age := float64(profile.GetAge(c.Profile.Birthday, viewer.RankedAt))
+	diff := math.Abs(age - viewer.LikeStats.AgeAverage)
+	return rankAgeScale / (rankAgeScale + diff)
+}
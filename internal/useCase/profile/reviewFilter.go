@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ReviewFilter decides whether the review has to be checked by a moderator before it is published
+type ReviewFilter interface {
+	IsSuspicious(message string) bool
+}
+
+// DefaultStopWords are the obscene words, the word which ends with "*" is a root and matches any word which
+// starts with it
+func DefaultStopWords() []string {
+	return []string{
+		"хуй*", "хуе*", "хуё*", "пизд*", "ебат*", "ебан*", "ёбан*", "еблан*", "бляд*", "блять", "сука", "суки",
+		"мудак*", "fuck*", "shit*", "bitch*", "cunt*",
+	}
+}
+
+// reviewWordBoundary matches the start or the end of the text and any rune which is not a letter or a digit,
+// \b is not used because it treats only the ASCII letters as the word characters
+const reviewWordBoundary = `[^\p{L}\p{N}]`
+
+var (
+	// reviewLinkPattern matches the urls, the bare domains and the telegram usernames
+	reviewLinkPattern = regexp.MustCompile(`(?i)(https?://|www\.|t\.me/|@[a-z0-9_]{5,}|(?:^|` + reviewWordBoundary +
+		`)[\p{L}\p{N}-]+\.(?:ru|com|net|org|info|me|io|su|рф)(?:$|` + reviewWordBoundary + `))`)
+	// reviewPhonePattern matches ten and more digits which may be separated by spaces, dashes and brackets
+	reviewPhonePattern = regexp.MustCompile(`\+?\d(?:[\s\-()]*\d){9,}`)
+)
+
+// TextReviewFilter holds the reviews which contain the stop words, the links or the phone numbers
+type TextReviewFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func NewTextReviewFilter(stopWords []string) *TextReviewFilter {
+	patterns := []*regexp.Regexp{reviewLinkPattern, reviewPhonePattern}
+	if p := stopWordsPattern(stopWords); p != nil {
+		patterns = append(patterns, p)
+	}
+	return &TextReviewFilter{patterns: patterns}
+}
+
+// stopWordsPattern matches the stop words as the whole words and the roots as the starts of the words,
+// nil is returned when there are no stop words
+func stopWordsPattern(stopWords []string) *regexp.Regexp {
+	words := make([]string, 0, len(stopWords))
+	for _, w := range stopWords {
+		w = strings.ToLower(strings.TrimSpace(w))
+		root, isRoot := strings.CutSuffix(w, "*")
+		if root == "" {
+			continue
+		}
+		if isRoot {
+			words = append(words, regexp.QuoteMeta(root)+`[\p{L}\p{N}]*`)
+			continue
+		}
+		words = append(words, regexp.QuoteMeta(root))
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?:^|` + reviewWordBoundary + `)(?:` + strings.Join(words, "|") + `)(?:$|` +
+		reviewWordBoundary + `)`)
+}
+
+func (f *TextReviewFilter) IsSuspicious(message string) bool {
+	text := strings.ToLower(message)
+	for _, p := range f.patterns {
+		if p.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package profile
+
+import (
+	"context"
+	"crypto/rand"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/appError"
+	"github.com/EvgeniyBudaev/love-server/internal/entity/profile"
+	"github.com/EvgeniyBudaev/love-server/internal/tracing"
+	"go.uber.org/zap"
+	"math/big"
+	"time"
+)
+
+// SelfieComparer compares the selfie with the images of the profile before the selfie is checked by a moderator.
+// The returned score from 0 to 1 is shown to the moderator, the higher score means the same person.
+type SelfieComparer interface {
+	Compare(ctx context.Context, selfiePath string, images []*profile.ImageProfile) (float64, error)
+}
+
+// AddSelfieChallenge issues the pose in which the selfie has to be taken. The challenge which has not expired yet is
+// returned again, so the pose can't be changed by asking for a new one.
+func (u *UseCaseProfile) AddSelfieChallenge(
+	ctx context.Context, profileID uint64) (*profile.SelfieVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.AddSelfieChallenge")
+	defer span.End()
+	last, isExist, err := u.profileRepo.FindLastSelfieVerificationByProfileID(ctx, profileID)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddSelfieChallenge, method FindLastSelfieVerificationByProfileID",
+			zap.Error(err))
+		return nil, err
+	}
+	if isExist {
+		switch {
+		case last.Status == profile.SelfieStatusApproved:
+			return nil, appError.NewConflict("profile has already been verified")
+		case last.Status == profile.SelfieStatusPending:
+			return nil, appError.NewConflict("selfie is waiting for moderation")
+		case last.Status == profile.SelfieStatusChallenged && last.ExpiresAt.After(time.Now().UTC()):
+			return last, nil
+		}
+	}
+	pose, err := newSelfiePose()
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddSelfieChallenge, method newSelfiePose", zap.Error(err))
+		return nil, err
+	}
+	verificationDto := &profile.SelfieVerificationProfile{
+		ProfileID: profileID,
+		Pose:      pose,
+		Status:    profile.SelfieStatusChallenged,
+		ExpiresAt: time.Now().UTC().Add(profile.SelfieChallengeTTL),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	response, err := u.profileRepo.AddSelfieVerification(ctx, verificationDto)
+	if err != nil {
+		u.logger.With(ctx).Error("error func AddSelfieChallenge, method AddSelfieVerification", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+// FindSelfieChallenge returns the challenge of the profile which waits for the selfie
+func (u *UseCaseProfile) FindSelfieChallenge(
+	ctx context.Context, profileID uint64, id uint64) (*profile.SelfieVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindSelfieChallenge")
+	defer span.End()
+	challenge, isExist, err := u.profileRepo.FindSelfieVerificationByID(ctx, id)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindSelfieChallenge, method FindSelfieVerificationByID", zap.Error(err))
+		return nil, err
+	}
+	if !isExist || challenge.ProfileID != profileID {
+		return nil, appError.NewNotFound("selfie challenge not found")
+	}
+	if challenge.Status != profile.SelfieStatusChallenged {
+		return nil, appError.NewConflict("selfie has already been uploaded")
+	}
+	if !challenge.ExpiresAt.After(time.Now().UTC()) {
+		return nil, appError.NewConflict("selfie challenge has expired")
+	}
+	return challenge, nil
+}
+
+// SubmitSelfie puts the uploaded selfie of the challenge into the moderation queue. The selfie is compared with the
+// images of the profile first when the comparer is set, a failed comparison leaves the score empty.
+func (u *UseCaseProfile) SubmitSelfie(ctx context.Context, challenge *profile.SelfieVerificationProfile,
+	filePath string) (*profile.SelfieVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SubmitSelfie")
+	defer span.End()
+	challenge.Status = profile.SelfieStatusPending
+	challenge.FilePath = filePath
+	challenge.UpdatedAt = time.Now().UTC()
+	if u.selfie != nil {
+		images, err := u.profileRepo.SelectListPublicImage(ctx, challenge.ProfileID)
+		if err != nil {
+			u.logger.With(ctx).Error("error func SubmitSelfie, method SelectListPublicImage", zap.Error(err))
+			return nil, err
+		}
+		score, err := u.selfie.Compare(ctx, filePath, images)
+		if err != nil {
+			u.logger.With(ctx).Warn("error func SubmitSelfie, method Compare", zap.Error(err))
+		} else {
+			challenge.Score = &score
+		}
+	}
+	response, err := u.profileRepo.UpdateSelfieVerification(ctx, challenge)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SubmitSelfie, method UpdateSelfieVerification", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+// ModerateSelfie sets the status chosen by a moderator to the pending selfie, the approved selfie verifies the
+// profile
+func (u *UseCaseProfile) ModerateSelfie(
+	ctx context.Context, id uint64, status string) (*profile.SelfieVerificationProfile, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.ModerateSelfie")
+	defer span.End()
+	verification, isExist, err := u.profileRepo.FindSelfieVerificationByID(ctx, id)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ModerateSelfie, method FindSelfieVerificationByID", zap.Error(err))
+		return nil, err
+	}
+	if !isExist {
+		return nil, appError.NewNotFound("selfie verification not found")
+	}
+	if verification.Status != profile.SelfieStatusPending {
+		return nil, appError.NewConflict("selfie is not waiting for moderation")
+	}
+	verification.Status = status
+	verification.UpdatedAt = time.Now().UTC()
+	response, err := u.profileRepo.UpdateSelfieVerification(ctx, verification)
+	if err != nil {
+		u.logger.With(ctx).Error("error func ModerateSelfie, method UpdateSelfieVerification", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+func (u *UseCaseProfile) FindSelfieVerificationByID(
+	ctx context.Context, id uint64) (*profile.SelfieVerificationProfile, bool, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.FindSelfieVerificationByID")
+	defer span.End()
+	response, isExist, err := u.profileRepo.FindSelfieVerificationByID(ctx, id)
+	if err != nil {
+		u.logger.With(ctx).Error("error func FindSelfieVerificationByID, method FindSelfieVerificationByID",
+			zap.Error(err))
+		return nil, false, err
+	}
+	return response, isExist, nil
+}
+
+func (u *UseCaseProfile) SelectListSelfieVerificationByStatus(ctx context.Context,
+	qp *profile.QueryParamsSelfieModerationList) (*profile.ResponseListSelfieModeration, error) {
+	ctx, span := tracing.Start(ctx, "UseCaseProfile.SelectListSelfieVerificationByStatus")
+	defer span.End()
+	response, err := u.profileRepo.SelectListSelfieVerificationByStatus(ctx, qp)
+	if err != nil {
+		u.logger.With(ctx).Error("error func SelectListSelfieVerificationByStatus,"+
+			" method SelectListSelfieVerificationByStatus", zap.Error(err))
+		return nil, err
+	}
+	return response, nil
+}
+
+// newSelfiePose returns the random pose from profile.SelfiePoses
+func newSelfiePose() (string, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(profile.SelfiePoses))))
+	if err != nil {
+		return "", err
+	}
+	return profile.SelfiePoses[i.Int64()], nil
+}
@@ -62,7 +62,7 @@ func (uc *UseCaseUser) Register(ctx context.Context, request RegisterRequest) (*
 	}
 	response, err := uc.identity.CreateUser(ctx, user, request.Password, "customer")
 	if err != nil {
-		uc.logger.Debug("error func Register, method CreateUser by path internal/useCase/user/user.go", zap.Error(err))
+		uc.logger.With(ctx).Error("error func Register, method CreateUser", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -84,8 +84,7 @@ func (uc *UseCaseUser) UpdateUser(ctx context.Context, request RequestUpdateUser
 	}
 	response, err := uc.identity.UpdateUser(ctx, user)
 	if err != nil {
-		uc.logger.Debug("error func UpdateUser, method UpdateUser by path internal/useCase/user/user.go",
-			zap.Error(err))
+		uc.logger.With(ctx).Error("error func UpdateUser, method UpdateUser", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -97,8 +96,7 @@ func (uc *UseCaseUser) DeleteUser(ctx context.Context, request RequestDeleteUser
 	}
 	err := uc.identity.DeleteUser(ctx, user)
 	if err != nil {
-		uc.logger.Debug("error func DeleteUser, method DeleteUser by path internal/useCase/user/user.go",
-			zap.Error(err))
+		uc.logger.With(ctx).Error("error func DeleteUser, method DeleteUser", zap.Error(err))
 		return err
 	}
 	return nil
@@ -107,8 +105,7 @@ func (uc *UseCaseUser) DeleteUser(ctx context.Context, request RequestDeleteUser
 func (uc *UseCaseUser) GetUserList(ctx context.Context, query QueryParamsUserList) ([]*gocloak.User, error) {
 	response, err := uc.identity.GetUserList(ctx, query)
 	if err != nil {
-		uc.logger.Debug("error func GetUserList, method GetUserList by path internal/useCase/user/user.go",
-			zap.Error(err))
+		uc.logger.With(ctx).Error("error func GetUserList, method GetUserList", zap.Error(err))
 		return nil, err
 	}
 	return response, nil
@@ -0,0 +1 @@
+DROP TABLE profile_swipes;
\ No newline at end of file
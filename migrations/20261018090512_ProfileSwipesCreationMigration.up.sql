@@ -0,0 +1,13 @@
+CREATE TABLE profile_swipes (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                human_id BIGINT NOT NULL,
+                                action VARCHAR NOT NULL,
+                                is_rewound BOOL NOT NULL,
+                                expires_at TIMESTAMP,
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+CREATE INDEX idx_profile_swipes_profile_id_human_id ON profile_swipes (profile_id, human_id);
@@ -0,0 +1 @@
+DROP TABLE profile_subscriptions;
\ No newline at end of file
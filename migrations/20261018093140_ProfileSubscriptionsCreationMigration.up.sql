@@ -0,0 +1,14 @@
+CREATE TABLE profile_subscriptions (
+                                       id BIGSERIAL NOT NULL PRIMARY KEY,
+                                       profile_id BIGINT NOT NULL,
+                                       plan VARCHAR NOT NULL,
+                                       period VARCHAR NOT NULL,
+                                       status VARCHAR NOT NULL,
+                                       started_at TIMESTAMP NOT NULL,
+                                       ended_at TIMESTAMP NOT NULL,
+                                       created_at TIMESTAMP NOT NULL,
+                                       updated_at TIMESTAMP NOT NULL,
+                                       CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+CREATE INDEX idx_profile_subscriptions_status_ended_at ON profile_subscriptions (status, ended_at);
@@ -0,0 +1 @@
+DROP TABLE profile_payments;
\ No newline at end of file
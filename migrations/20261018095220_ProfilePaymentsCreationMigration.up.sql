@@ -0,0 +1,15 @@
+CREATE TABLE profile_payments (
+                                  id BIGSERIAL NOT NULL PRIMARY KEY,
+                                  profile_id BIGINT NOT NULL,
+                                  plan VARCHAR NOT NULL,
+                                  period VARCHAR NOT NULL,
+                                  amount INTEGER NOT NULL,
+                                  currency VARCHAR NOT NULL,
+                                  status VARCHAR NOT NULL,
+                                  payload VARCHAR NOT NULL UNIQUE,
+                                  telegram_payment_charge_id VARCHAR NOT NULL,
+                                  provider_payment_charge_id VARCHAR NOT NULL,
+                                  created_at TIMESTAMP NOT NULL,
+                                  updated_at TIMESTAMP NOT NULL,
+                                  CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
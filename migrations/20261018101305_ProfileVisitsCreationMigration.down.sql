@@ -0,0 +1 @@
+DROP TABLE profile_visits;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE profile_visits (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                viewer_id BIGINT NOT NULL,
+                                visited_on DATE NOT NULL,
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id),
+                                CONSTRAINT fk_viewer_id FOREIGN KEY (viewer_id) REFERENCES profiles (id),
+                                CONSTRAINT uq_profile_visits_profile_id_viewer_id_visited_on
+                                    UNIQUE (profile_id, viewer_id, visited_on)
+);
+
+CREATE INDEX idx_profile_visits_updated_at ON profile_visits (updated_at);
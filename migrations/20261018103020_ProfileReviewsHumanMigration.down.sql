@@ -0,0 +1,3 @@
+DROP INDEX uq_profile_reviews_profile_id_human_id;
+
+ALTER TABLE profile_reviews DROP COLUMN human_id;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE profile_reviews ADD COLUMN human_id BIGINT;
+
+ALTER TABLE profile_reviews ADD CONSTRAINT fk_human_id FOREIGN KEY (human_id) REFERENCES profiles (id);
+
+CREATE UNIQUE INDEX uq_profile_reviews_profile_id_human_id ON profile_reviews (profile_id, human_id)
+    WHERE has_deleted = false;
@@ -0,0 +1,5 @@
+DROP TABLE profile_review_reports;
+
+DROP INDEX idx_profile_reviews_status;
+
+ALTER TABLE profile_reviews DROP COLUMN status;
\ No newline at end of file
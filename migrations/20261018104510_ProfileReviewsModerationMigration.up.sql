@@ -0,0 +1,15 @@
+ALTER TABLE profile_reviews ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published';
+
+CREATE INDEX idx_profile_reviews_status ON profile_reviews (status);
+
+CREATE TABLE profile_review_reports (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                review_id BIGINT NOT NULL,
+                                profile_id BIGINT NOT NULL,
+                                reason VARCHAR(255),
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_review_id FOREIGN KEY (review_id) REFERENCES profile_reviews (id),
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id),
+                                CONSTRAINT uq_profile_review_reports_review_id_profile_id UNIQUE (review_id, profile_id)
+);
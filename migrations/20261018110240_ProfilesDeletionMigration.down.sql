@@ -0,0 +1,5 @@
+DROP TABLE profile_deletion_audits;
+
+DROP INDEX idx_profiles_deleted_at;
+
+ALTER TABLE profiles DROP COLUMN deleted_at;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE profiles ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_profiles_deleted_at ON profiles (deleted_at) WHERE is_deleted = true;
+
+CREATE TABLE profile_deletion_audits (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                deleted_at TIMESTAMP NOT NULL,
+                                erased_at TIMESTAMP NOT NULL,
+                                removed_rows JSONB NOT NULL,
+                                removed_files INTEGER NOT NULL,
+                                is_identity_removed BOOL NOT NULL,
+                                created_at TIMESTAMP NOT NULL
+);
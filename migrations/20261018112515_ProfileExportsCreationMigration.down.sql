@@ -0,0 +1 @@
+DROP TABLE profile_exports;
\ No newline at end of file
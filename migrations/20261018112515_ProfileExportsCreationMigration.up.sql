@@ -0,0 +1,13 @@
+CREATE TABLE profile_exports (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                status VARCHAR NOT NULL,
+                                token VARCHAR NOT NULL,
+                                file_path VARCHAR,
+                                expires_at TIMESTAMP,
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+CREATE INDEX idx_profile_exports_status ON profile_exports (status);
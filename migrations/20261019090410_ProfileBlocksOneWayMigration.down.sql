@@ -0,0 +1,2 @@
+DROP INDEX idx_profile_blocks_blocked_user_id;
+DROP INDEX idx_profile_blocks_profile_id_blocked_user_id;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- A block used to be stored twice, the second row was added for the blocked profile right after the first one.
+-- A block is stored once by the blocker now and is applied in both directions by the queries.
+DELETE FROM profile_blocks m
+    USING profile_blocks o
+WHERE o.profile_id = m.blocked_user_id AND o.blocked_user_id = m.profile_id AND o.id < m.id
+  AND m.created_at - o.created_at BETWEEN INTERVAL '0' AND INTERVAL '1 second';
+
+CREATE INDEX idx_profile_blocks_profile_id_blocked_user_id ON profile_blocks (profile_id, blocked_user_id);
+CREATE INDEX idx_profile_blocks_blocked_user_id ON profile_blocks (blocked_user_id);
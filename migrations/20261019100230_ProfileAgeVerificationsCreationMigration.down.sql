@@ -0,0 +1 @@
+DROP TABLE profile_age_verifications;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE profile_age_verifications (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                status VARCHAR(20) NOT NULL,
+                                reason VARCHAR(255) NOT NULL,
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+CREATE INDEX idx_profile_age_verifications_status ON profile_age_verifications (status);
+
+CREATE UNIQUE INDEX uq_profile_age_verifications_profile_id_pending ON profile_age_verifications (profile_id)
+    WHERE status = 'pending';
@@ -0,0 +1 @@
+DROP TABLE profile_selfie_verifications;
\ No newline at end of file
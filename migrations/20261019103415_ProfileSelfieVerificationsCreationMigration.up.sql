@@ -0,0 +1,17 @@
+CREATE TABLE profile_selfie_verifications (
+                                id BIGSERIAL NOT NULL PRIMARY KEY,
+                                profile_id BIGINT NOT NULL,
+                                pose VARCHAR(50) NOT NULL,
+                                status VARCHAR(20) NOT NULL,
+                                file_path VARCHAR NOT NULL DEFAULT '',
+                                score DOUBLE PRECISION,
+                                expires_at TIMESTAMP NOT NULL,
+                                created_at TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP NOT NULL,
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+CREATE INDEX idx_profile_selfie_verifications_status ON profile_selfie_verifications (status);
+
+CREATE INDEX idx_profile_selfie_verifications_profile_id_approved ON profile_selfie_verifications (profile_id)
+    WHERE status = 'approved';
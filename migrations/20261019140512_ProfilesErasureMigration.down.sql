@@ -0,0 +1,5 @@
+DROP INDEX idx_profiles_deleted_at;
+
+CREATE INDEX idx_profiles_deleted_at ON profiles (deleted_at) WHERE is_deleted = true;
+
+ALTER TABLE profiles DROP COLUMN erased_at;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- The erased profile stays as a tombstone with is_deleted=true, erased_at keeps it out of the next erasure runs
+ALTER TABLE profiles ADD COLUMN erased_at TIMESTAMP;
+
+UPDATE profiles p SET erased_at = a.erased_at
+FROM (SELECT profile_id, MIN(erased_at) AS erased_at FROM profile_deletion_audits GROUP BY profile_id) a
+WHERE a.profile_id = p.id;
+
+DROP INDEX idx_profiles_deleted_at;
+
+CREATE INDEX idx_profiles_deleted_at ON profiles (deleted_at) WHERE is_deleted = true AND erased_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE profile_navigators DROP COLUMN jumps_started_at;
+ALTER TABLE profile_navigators DROP COLUMN jump_count;
+ALTER TABLE profile_navigators DROP COLUMN moved_at;
\ No newline at end of file
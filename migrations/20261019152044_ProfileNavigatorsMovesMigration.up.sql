@@ -0,0 +1,4 @@
+-- The last move and the jumps of the current window are kept to refuse the location jumps
+ALTER TABLE profile_navigators ADD COLUMN moved_at TIMESTAMP;
+ALTER TABLE profile_navigators ADD COLUMN jump_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE profile_navigators ADD COLUMN jumps_started_at TIMESTAMP;
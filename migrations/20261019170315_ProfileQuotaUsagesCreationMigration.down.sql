@@ -0,0 +1 @@
+DROP TABLE profile_quota_usages;
\ No newline at end of file
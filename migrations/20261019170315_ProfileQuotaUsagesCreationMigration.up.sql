@@ -0,0 +1,21 @@
+-- The actions of the day are counted here, the count is checked and increased in one statement
+CREATE TABLE profile_quota_usages (
+                                profile_id BIGINT NOT NULL,
+                                action VARCHAR(20) NOT NULL,
+                                day DATE NOT NULL,
+                                used BIGINT NOT NULL DEFAULT 0,
+                                PRIMARY KEY (profile_id, action, day),
+                                CONSTRAINT fk_profile_id FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+
+INSERT INTO profile_quota_usages (profile_id, action, day, used)
+SELECT profile_id, 'like', (NOW() AT TIME ZONE 'UTC')::date, COUNT(*) FROM profile_likes
+WHERE created_at::date = (NOW() AT TIME ZONE 'UTC')::date GROUP BY profile_id;
+
+INSERT INTO profile_quota_usages (profile_id, action, day, used)
+SELECT profile_id, 'complaint', (NOW() AT TIME ZONE 'UTC')::date, COUNT(*) FROM profile_complaints
+WHERE created_at::date = (NOW() AT TIME ZONE 'UTC')::date GROUP BY profile_id;
+
+INSERT INTO profile_quota_usages (profile_id, action, day, used)
+SELECT profile_id, 'review', (NOW() AT TIME ZONE 'UTC')::date, COUNT(*) FROM profile_reviews
+WHERE created_at::date = (NOW() AT TIME ZONE 'UTC')::date GROUP BY profile_id;